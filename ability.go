@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+)
+
+const hiddenAbilityOdds = 20
+
+type RespAbility struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	EffectEntries []struct {
+		Effect      string `json:"effect"`
+		ShortEffect string `json:"short_effect"`
+		Language    struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"language"`
+	} `json:"effect_entries"`
+	Pokemon []struct {
+		IsHidden bool `json:"is_hidden"`
+		Slot     int  `json:"slot"`
+		Pokemon  struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"pokemon"`
+	} `json:"pokemon"`
+}
+
+type abilityResult struct {
+	Name    string        `json:"name"`
+	Effect  string        `json:"effect"`
+	Pokemon []abilitySlot `json:"pokemon"`
+}
+
+func (r abilityResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Ability: %s\n", r.Name)
+	if r.Effect != "" {
+		fmt.Fprintf(w, "Effect: %s\n", r.Effect)
+	}
+	fmt.Fprintln(w, "Pokemon:")
+	for _, p := range r.Pokemon {
+		if p.Hidden {
+			fmt.Fprintf(w, " - %s (hidden)\n", p.Name)
+			continue
+		}
+		fmt.Fprintf(w, " - %s\n", p.Name)
+	}
+}
+
+func commandAbility(cfg *config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: ability <ability_name>")
+	}
+
+	ability, err := getAbility(cfg, normalizeName(args[0]))
+	if err != nil {
+		return suggestMatches(cfg, err)
+	}
+
+	result := abilityResult{Name: ability.Name, Pokemon: []abilitySlot{}}
+	for _, entry := range ability.EffectEntries {
+		if entry.Language.Name == "en" {
+			result.Effect = entry.ShortEffect
+			break
+		}
+	}
+	for _, p := range ability.Pokemon {
+		result.Pokemon = append(result.Pokemon, abilitySlot{p.Pokemon.Name, p.IsHidden})
+	}
+	return emit(cfg, result)
+}
+
+func rollAbility(rng *rand.Rand, pokemon RespPokemon) string {
+	regular := []string{}
+	hidden := []string{}
+	for _, a := range pokemon.Abilities {
+		if a.IsHidden {
+			hidden = append(hidden, a.Ability.Name)
+		} else {
+			regular = append(regular, a.Ability.Name)
+		}
+	}
+
+	if len(hidden) > 0 && (len(regular) == 0 || rng.Intn(hiddenAbilityOdds) == 0) {
+		return hidden[rng.Intn(len(hidden))]
+	}
+	if len(regular) == 0 {
+		return ""
+	}
+	return regular[rng.Intn(len(regular))]
+}
+
+func abilityAfterEvolution(ability string, from, to RespPokemon) string {
+	slot := 0
+	for _, a := range from.Abilities {
+		if a.Ability.Name == ability {
+			slot = a.Slot
+		}
+	}
+	for _, a := range to.Abilities {
+		if a.Slot == slot {
+			return a.Ability.Name
+		}
+	}
+	return ability
+}
+
+func getAbility(cfg *config, abilityName string) (RespAbility, error) {
+	url := "https://pokeapi.co/api/v2/ability/" + abilityName
+
+	if val, ok := cfg.pokeapiClient.Get(url); ok {
+		var abilityResp RespAbility
+		err := json.Unmarshal(val, &abilityResp)
+		return abilityResp, err
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return RespAbility{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return RespAbility{}, &notFoundError{Kind: "ability", Name: abilityName}
+	}
+	if res.StatusCode > 299 {
+		return RespAbility{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return RespAbility{}, err
+	}
+
+	cfg.pokeapiClient.Add(url, body)
+
+	var abilityResp RespAbility
+	err = json.Unmarshal(body, &abilityResp)
+	if err != nil {
+		return RespAbility{}, err
+	}
+
+	return abilityResp, nil
+}
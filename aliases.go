@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+type userConfig struct {
+	Aliases map[string]string `json:"aliases"`
+}
+
+// loadUserConfig reads the user config file at cfg.configPath. A missing
+// file is not an error.
+func loadUserConfig(cfg *config) error {
+	data, err := os.ReadFile(cfg.configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var userCfg userConfig
+	if err := json.Unmarshal(data, &userCfg); err != nil {
+		return fmt.Errorf("reading %s: %w", cfg.configPath, err)
+	}
+	for name, expansion := range userCfg.Aliases {
+		cfg.aliases[name] = expansion
+	}
+	return nil
+}
+
+// saveUserConfig writes the user config file. It does nothing when no config
+// path is set, as in tests.
+func saveUserConfig(cfg *config) error {
+	if cfg.configPath == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(userConfig{Aliases: cfg.aliases}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cfg.configPath, append(data, '\n'), 0o644)
+}
+
+type aliasesResult struct {
+	Aliases []aliasEntry `json:"aliases"`
+}
+
+type aliasEntry struct {
+	Name      string `json:"name"`
+	Expansion string `json:"expansion"`
+}
+
+func (r aliasesResult) renderText(w io.Writer) {
+	if len(r.Aliases) == 0 {
+		fmt.Fprintln(w, "No aliases defined")
+		return
+	}
+	for _, alias := range r.Aliases {
+		fmt.Fprintf(w, "%s = %s\n", alias.Name, alias.Expansion)
+	}
+}
+
+func commandAlias(cfg *config, args ...string) error {
+	if len(args) == 0 {
+		result := aliasesResult{Aliases: []aliasEntry{}}
+		for name, expansion := range cfg.aliases {
+			result.Aliases = append(result.Aliases, aliasEntry{name, expansion})
+		}
+		sort.Slice(result.Aliases, func(i, j int) bool {
+			return result.Aliases[i].Name < result.Aliases[j].Name
+		})
+		return emit(cfg, result)
+	}
+
+	// Accept "alias name = expansion", "alias name=expansion" and
+	// "alias name expansion".
+	name, rest, _ := strings.Cut(args[0], "=")
+	words := args[1:]
+	if rest != "" {
+		words = append([]string{rest}, words...)
+	} else if len(words) > 0 && words[0] == "=" {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("usage: alias <name> = <commands>")
+	}
+
+	name = strings.ToLower(name)
+	if name == "" || strings.ContainsAny(name, " \t;'\"\\$") {
+		return fmt.Errorf("invalid alias name %q", name)
+	}
+	if _, exists := getCommands()[name]; exists {
+		return fmt.Errorf("%s is already a command", name)
+	}
+
+	// A body given as one quoted word is kept as typed, so its ";" and
+	// placeholders work. Otherwise the words are quoted again so an argument
+	// like "Sir Fluffy" stays one argument, leaving bare placeholders alone.
+	body := words[0]
+	if len(words) > 1 {
+		quoted := make([]string, len(words))
+		for i, word := range words {
+			quoted[i] = word
+			if !isPlaceholder(word) {
+				quoted[i] = quoteWord(word)
+			}
+		}
+		body = strings.Join(quoted, " ")
+	}
+
+	cfg.aliases[name] = body
+	if err := saveUserConfig(cfg); err != nil {
+		return err
+	}
+	return emit(cfg, messageResult{fmt.Sprintf("%s = %s", name, cfg.aliases[name])})
+}
+
+func commandUnalias(cfg *config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: unalias <name>")
+	}
+
+	name := strings.ToLower(args[0])
+	if _, exists := cfg.aliases[name]; !exists {
+		return fmt.Errorf("no alias named %s", name)
+	}
+
+	delete(cfg.aliases, name)
+	if err := saveUserConfig(cfg); err != nil {
+		return err
+	}
+	return emit(cfg, messageResult{fmt.Sprintf("removed alias %s", name)})
+}
+
+// runAlias expands a user alias with args and runs the resulting commands in
+// order, stopping at the first error. An alias that ends up calling itself is
+// an error rather than an endless loop.
+func runAlias(cfg *config, commands map[string]cliCommand, name string, args []string) error {
+	if cfg.runningAliases[name] {
+		return fmt.Errorf("alias %s calls itself", name)
+	}
+	if cfg.runningAliases == nil {
+		cfg.runningAliases = make(map[string]bool)
+	}
+	cfg.runningAliases[name] = true
+	defer delete(cfg.runningAliases, name)
+
+	for _, input := range splitCommands(expandAlias(cfg.aliases[name], args)) {
+		if strings.TrimSpace(input) == "" {
+			continue
+		}
+		if err := runCommand(cfg, commands, input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandAlias substitutes $1 to $9 with the matching argument and $@ with all
+// of them. An expansion without any placeholders gets the arguments appended,
+// so "alias c2 = catch" works like the command it stands for.
+func expandAlias(expansion string, args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quoteWord(arg)
+	}
+
+	var b strings.Builder
+	substituted := false
+	for i := 0; i < len(expansion); i++ {
+		if expansion[i] != '$' || i+1 == len(expansion) {
+			b.WriteByte(expansion[i])
+			continue
+		}
+
+		next := expansion[i+1]
+		switch {
+		case next == '@':
+			b.WriteString(strings.Join(quoted, " "))
+		case next >= '1' && next <= '9':
+			if n := int(next - '1'); n < len(quoted) {
+				b.WriteString(quoted[n])
+			}
+		default:
+			b.WriteByte('$')
+			continue
+		}
+		substituted = true
+		i++
+	}
+
+	if !substituted && len(quoted) > 0 {
+		b.WriteString(" " + strings.Join(quoted, " "))
+	}
+	return b.String()
+}
+
+// isPlaceholder reports whether word is one of the $1-$9 or $@ placeholders
+// expandAlias fills in.
+func isPlaceholder(word string) bool {
+	return len(word) == 2 && word[0] == '$' && (word[1] == '@' || word[1] >= '1' && word[1] <= '9')
+}
+
+func completeAliases(cfg *config, args ...string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.aliases))
+	for name := range cfg.aliases {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandAlias(t *testing.T) {
+	cases := []struct {
+		expansion string
+		args      []string
+		expected  string
+	}{
+		{"explore $1; catch $2", []string{"pastoria-city-area", "pikachu"}, "explore pastoria-city-area; catch pikachu"},
+		{"catch", []string{"pikachu"}, "catch pikachu"},
+		{"nickname $1 $2", []string{"pikachu", "Sir Fluffy"}, "nickname pikachu 'Sir Fluffy'"},
+		{"catch $@", []string{"a", "b"}, "catch a b"},
+		{"catch $2", []string{"a"}, "catch "},
+		{"echo $x $", nil, "echo $x $"},
+	}
+
+	for _, c := range cases {
+		if actual := expandAlias(c.expansion, c.args); actual != c.expected {
+			t.Errorf("expandAlias(%q, %q) = %q, expected %q", c.expansion, c.args, actual, c.expected)
+		}
+	}
+}
+
+func TestUserConfigRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	cfg := &config{aliases: map[string]string{}, configPath: path}
+	if err := loadUserConfig(cfg); err != nil {
+		t.Fatalf("loading a missing config: %v", err)
+	}
+
+	cfg.aliases["hunt"] = "explore $1; catch $2"
+	if err := saveUserConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := &config{aliases: map[string]string{}, configPath: path}
+	if err := loadUserConfig(loaded); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.aliases["hunt"] != "explore $1; catch $2" {
+		t.Errorf("aliases = %v, expected hunt to be restored", loaded.aliases)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func printUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "Usage:")
+	fmt.Fprintln(out, "  pokedexcli [flags]                       start the interactive Pokedex")
+	fmt.Fprintln(out, "  pokedexcli [flags] <command> [args...]   run a single command and exit")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Commands:")
+
+	for _, category := range newHelpResult(getCommands()).Categories {
+		fmt.Fprintf(out, "  %s:\n", category.Name)
+		for _, entry := range category.Commands {
+			fmt.Fprintf(out, "    %-10s %s\n", entry.Name, entry.Description)
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Run 'pokedexcli <command> --help' for details on a command.")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Flags:")
+	flag.PrintDefaults()
+}
+
+func printCommandUsage(w io.Writer, command cliCommand) {
+	newCommandHelpResult(command, "pokedexcli ").renderText(w)
+}
+
+// runSubcommand runs a single command given on the command line and returns
+// the process exit status.
+func runSubcommand(cfg *config, args []string) int {
+	commands := getCommands()
+
+	name := strings.ToLower(args[0])
+	command, exists := commands[name]
+	if _, isAlias := cfg.aliases[name]; !exists && isAlias {
+		return finishSubcommand(cfg, runAlias(cfg, commands, name, args[1:]))
+	}
+	if !exists {
+		fmt.Fprintf(cfg.errOut, "Unknown command %q\n\n", args[0])
+		flag.Usage()
+		return 2
+	}
+
+	// A help flag anywhere before "--" asks for the command's usage; after
+	// it, it is passed on like any other argument.
+	for _, arg := range args[1:] {
+		if arg == "--" {
+			break
+		}
+		if arg == "-h" || arg == "-help" || arg == "--help" {
+			printCommandUsage(cfg.out, command)
+			return 0
+		}
+	}
+
+	return finishSubcommand(cfg, command.callback(cfg, args[1:]...))
+}
+
+// finishSubcommand reports err and turns it into the process exit status.
+func finishSubcommand(cfg *config, err error) int {
+	if err == errExit {
+		shutdown(cfg)
+		return 0
+	}
+	if err != nil {
+		reportError(cfg, err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRunSubcommand(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     []string
+		status   int
+		expected string
+	}{
+		{name: "command", args: []string{"seed", "5"}, status: 0, expected: "Seed set to 5"},
+		{name: "error", args: []string{"catch"}, status: 1, expected: "Error: usage: catch <pokemon_name>"},
+		{name: "unknown", args: []string{"fly", "pikachu"}, status: 2, expected: `Unknown command "fly"`},
+		{name: "help", args: []string{"catch", "--help"}, status: 0, expected: "pokedexcli catch <pokemon_name>"},
+		{name: "short help", args: []string{"pokedex", "--sort", "name", "-h"}, status: 0, expected: "pokedexcli pokedex"},
+		{name: "help after --", args: []string{"pokedex", "--", "-h"}, status: 1, expected: "Error: usage: pokedex"},
+		{name: "user alias", args: []string{"s5"}, status: 0, expected: "Seed set to 5"},
+		{name: "exit", args: []string{"exit"}, status: 0},
+	}
+
+	// An unknown command also prints the program usage to stderr.
+	flag.CommandLine.SetOutput(io.Discard)
+	t.Cleanup(func() { flag.CommandLine.SetOutput(nil) })
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cfg := newTestConfig(t, &out)
+			cfg.aliases["s5"] = "seed 5"
+
+			if status := runSubcommand(cfg, tc.args); status != tc.status {
+				t.Errorf("runSubcommand(%q) = %d, expected %d", tc.args, status, tc.status)
+			}
+			if !strings.Contains(out.String(), tc.expected) {
+				t.Errorf("output %q doesn't contain %q", out.String(), tc.expected)
+			}
+		})
+	}
+}
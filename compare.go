@@ -0,0 +1,284 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type RespType struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	DamageRelations struct {
+		DoubleDamageFrom []namedAPIResource `json:"double_damage_from"`
+		HalfDamageFrom   []namedAPIResource `json:"half_damage_from"`
+		NoDamageFrom     []namedAPIResource `json:"no_damage_from"`
+		DoubleDamageTo   []namedAPIResource `json:"double_damage_to"`
+		HalfDamageTo     []namedAPIResource `json:"half_damage_to"`
+		NoDamageTo       []namedAPIResource `json:"no_damage_to"`
+	} `json:"damage_relations"`
+}
+
+type compareResult struct {
+	Pokemon []comparedPokemon `json:"pokemon"`
+	// Matchups lists the attacking types that don't hit every compared
+	// pokemon equally hard.
+	Matchups []matchupRow `json:"matchups"`
+}
+
+type comparedPokemon struct {
+	Name          string        `json:"name"`
+	Types         []string      `json:"types"`
+	Abilities     []abilitySlot `json:"abilities"`
+	Height        int           `json:"height"`
+	Weight        int           `json:"weight"`
+	Stats         []statValue   `json:"stats"`
+	BaseStatTotal int           `json:"base_stat_total"`
+}
+
+type matchupRow struct {
+	Type        string    `json:"type"`
+	Multipliers []float64 `json:"multipliers"`
+}
+
+func (r compareResult) renderText(w io.Writer) {
+	rows := [][]string{{""}}
+	for _, pokemon := range r.Pokemon {
+		rows[0] = append(rows[0], pokemon.Name)
+	}
+
+	row := []string{"types"}
+	for _, pokemon := range r.Pokemon {
+		row = append(row, strings.Join(pokemon.Types, "/"))
+	}
+	rows = append(rows, row)
+
+	abilityRows := 0
+	for _, pokemon := range r.Pokemon {
+		abilityRows = max(abilityRows, len(pokemon.Abilities))
+	}
+	for i := 0; i < abilityRows; i++ {
+		row := []string{""}
+		if i == 0 {
+			row[0] = "abilities"
+		}
+		for _, pokemon := range r.Pokemon {
+			cell := ""
+			if i < len(pokemon.Abilities) {
+				cell = pokemon.Abilities[i].Name
+				if pokemon.Abilities[i].Hidden {
+					cell += " (hidden)"
+				}
+			}
+			row = append(row, cell)
+		}
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, compareValues("height", r.Pokemon, false, func(p comparedPokemon) int { return p.Height }))
+	rows = append(rows, compareValues("weight", r.Pokemon, false, func(p comparedPokemon) int { return p.Weight }))
+	if len(r.Pokemon) > 0 {
+		for _, stat := range r.Pokemon[0].Stats {
+			rows = append(rows, compareValues(stat.Name, r.Pokemon, true, func(p comparedPokemon) int {
+				i := slices.IndexFunc(p.Stats, func(s statValue) bool { return s.Name == stat.Name })
+				if i < 0 {
+					return 0
+				}
+				return p.Stats[i].Value
+			}))
+		}
+	}
+	rows = append(rows, compareValues("total", r.Pokemon, true, func(p comparedPokemon) int { return p.BaseStatTotal }))
+
+	matchupStart := len(rows)
+	for _, matchup := range r.Matchups {
+		row := []string{matchup.Type}
+		for _, multiplier := range matchup.Multipliers {
+			row = append(row, strconv.FormatFloat(multiplier, 'g', -1, 64)+"x")
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	printRow := func(row []string) {
+		line := ""
+		for i, cell := range row {
+			line += fmt.Sprintf("%-*s  ", widths[i], cell)
+		}
+		fmt.Fprintln(w, strings.TrimRight(line, " "))
+	}
+
+	for _, row := range rows[:matchupStart] {
+		printRow(row)
+	}
+	fmt.Fprintln(w, "(* marks the highest base stat)")
+
+	if len(r.Matchups) == 0 {
+		fmt.Fprintln(w, "All of them take the same damage from every type.")
+		return
+	}
+	fmt.Fprintln(w, "Damage taken where they differ:")
+	for _, row := range rows[matchupStart:] {
+		printRow(row)
+	}
+}
+
+// compareValues makes a table row of one number per pokemon. With highlight,
+// the highest value gets a * unless all of them are the same.
+func compareValues(label string, pokemon []comparedPokemon, highlight bool, value func(comparedPokemon) int) []string {
+	best, same := 0, true
+	for i, p := range pokemon {
+		best = max(best, value(p))
+		if i > 0 && value(p) != value(pokemon[0]) {
+			same = false
+		}
+	}
+
+	row := []string{label}
+	for _, p := range pokemon {
+		cell := strconv.Itoa(value(p))
+		if highlight && !same && value(p) == best {
+			cell += " *"
+		}
+		row = append(row, cell)
+	}
+	return row
+}
+
+func commandCompare(cfg *config, args ...string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: compare <pokemon_name> <pokemon_name> [<pokemon_name>...]")
+	}
+
+	result := compareResult{Pokemon: []comparedPokemon{}, Matchups: []matchupRow{}}
+	damageTaken := []map[string]float64{}
+	typeIDs := map[string]int{}
+
+	for _, arg := range args {
+		pokemon, err := resolvePokemon(cfg, normalizeName(arg))
+		if err != nil {
+			return err
+		}
+
+		compared := comparedPokemon{
+			Name:          pokemon.Name,
+			Types:         []string{},
+			Abilities:     []abilitySlot{},
+			Height:        pokemon.Height,
+			Weight:        pokemon.Weight,
+			Stats:         []statValue{},
+			BaseStatTotal: baseStatTotal(pokemon),
+		}
+		for _, stat := range pokemon.Stats {
+			compared.Stats = append(compared.Stats, statValue{stat.Stat.Name, stat.BaseStat})
+		}
+		for _, abilityInfo := range pokemon.Abilities {
+			compared.Abilities = append(compared.Abilities, abilitySlot{abilityInfo.Ability.Name, abilityInfo.IsHidden})
+		}
+
+		taken := map[string]float64{}
+		for _, typeInfo := range pokemon.Types {
+			compared.Types = append(compared.Types, typeInfo.Type.Name)
+
+			typeResp, err := getType(cfg, typeInfo.Type.Name)
+			if err != nil {
+				return err
+			}
+			relations := typeResp.DamageRelations
+			for _, relation := range []struct {
+				types      []namedAPIResource
+				multiplier float64
+			}{
+				{relations.DoubleDamageFrom, 2},
+				{relations.HalfDamageFrom, 0.5},
+				{relations.NoDamageFrom, 0},
+			} {
+				for _, attacker := range relation.types {
+					typeIDs[attacker.Name] = resourceID(attacker.URL)
+					current, ok := taken[attacker.Name]
+					if !ok {
+						current = 1
+					}
+					taken[attacker.Name] = current * relation.multiplier
+				}
+			}
+		}
+
+		result.Pokemon = append(result.Pokemon, compared)
+		damageTaken = append(damageTaken, taken)
+	}
+
+	attackers := []string{}
+	for name := range typeIDs {
+		attackers = append(attackers, name)
+	}
+	slices.SortFunc(attackers, func(a, b string) int { return cmp.Compare(typeIDs[a], typeIDs[b]) })
+
+	for _, attacker := range attackers {
+		row := matchupRow{Type: attacker}
+		for _, taken := range damageTaken {
+			multiplier, ok := taken[attacker]
+			if !ok {
+				multiplier = 1
+			}
+			row.Multipliers = append(row.Multipliers, multiplier)
+		}
+		if slices.ContainsFunc(row.Multipliers, func(m float64) bool { return m != row.Multipliers[0] }) {
+			result.Matchups = append(result.Matchups, row)
+		}
+	}
+
+	return emit(cfg, result)
+}
+
+func completeCompare(cfg *config, args ...string) []string {
+	return completePokemon(cfg)
+}
+
+func getType(cfg *config, typeName string) (RespType, error) {
+	url := "https://pokeapi.co/api/v2/type/" + typeName
+
+	if val, ok := cfg.pokeapiClient.Get(url); ok {
+		var typeResp RespType
+		err := json.Unmarshal(val, &typeResp)
+		return typeResp, err
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return RespType{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return RespType{}, &notFoundError{Kind: "type", Name: typeName}
+	}
+	if res.StatusCode > 299 {
+		return RespType{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return RespType{}, err
+	}
+
+	cfg.pokeapiClient.Add(url, body)
+
+	var typeResp RespType
+	err = json.Unmarshal(body, &typeResp)
+	if err != nil {
+		return RespType{}, err
+	}
+
+	return typeResp, nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompareValues(t *testing.T) {
+	pokemon := []comparedPokemon{{Height: 4}, {Height: 9}, {Height: 9}}
+	height := func(p comparedPokemon) int { return p.Height }
+
+	if row := compareValues("height", pokemon, true, height); !slices.Equal(row, []string{"height", "4", "9 *", "9 *"}) {
+		t.Errorf("highlighted row = %q", row)
+	}
+	if row := compareValues("height", pokemon, false, height); !slices.Equal(row, []string{"height", "4", "9", "9"}) {
+		t.Errorf("plain row = %q", row)
+	}
+	if row := compareValues("height", pokemon[1:], true, height); !slices.Equal(row, []string{"height", "9", "9"}) {
+		t.Errorf("row of equal values = %q", row)
+	}
+}
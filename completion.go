@@ -0,0 +1,128 @@
+package main
+
+import (
+	"sort"
+	"strings"
+)
+
+func completeInput(cfg *config, commands map[string]cliCommand, head string) []string {
+	fields := strings.Fields(strings.ToLower(head))
+	if len(fields) == 0 || strings.HasSuffix(head, " ") {
+		fields = append(fields, "")
+	}
+	word := fields[len(fields)-1]
+
+	if len(fields) == 1 {
+		names := make([]string, 0, len(commands))
+		for name := range commands {
+			names = append(names, name)
+		}
+		for name := range cfg.aliases {
+			names = append(names, name)
+		}
+		return filterCompletions(names, word)
+	}
+
+	command, exists := commands[fields[0]]
+	if !exists || command.completer == nil {
+		return nil
+	}
+	return filterCompletions(command.completer(cfg, fields[1:len(fields)-1]...), word)
+}
+
+func filterCompletions(candidates []string, prefix string) []string {
+	seen := make(map[string]bool)
+	matches := []string{}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) && !seen[candidate] {
+			seen[candidate] = true
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+func completeAreas(cfg *config, args ...string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.knownAreas))
+	for name := range cfg.knownAreas {
+		names = append(names, name)
+	}
+	return names
+}
+
+func completePokemon(cfg *config, args ...string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	names := append([]string{}, cfg.lastExplored...)
+	species, err := getSpeciesNames(cfg)
+	if err == nil {
+		names = append(names, species...)
+	}
+	return names
+}
+
+func completeOwned(cfg *config, args ...string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.caughtPokemon))
+	for name := range cfg.caughtPokemon {
+		names = append(names, name)
+	}
+	return names
+}
+
+func completeGive(cfg *config, args ...string) []string {
+	switch len(args) {
+	case 0:
+		return completeOwned(cfg)
+	case 1:
+		names := make([]string, 0, len(cfg.bag))
+		for name := range cfg.bag {
+			names = append(names, name)
+		}
+		return names
+	default:
+		return nil
+	}
+}
+
+func completeSet(cfg *config, args ...string) []string {
+	switch {
+	case len(args) == 0:
+		return []string{"shiny-odds", "version", "language", "output"}
+	case len(args) == 1 && args[0] == "language":
+		return languages
+	case len(args) == 1 && args[0] == "output":
+		return outputFormats
+	default:
+		return nil
+	}
+}
+
+func getSpeciesNames(cfg *config) ([]string, error) {
+	names := []string{}
+	for species, err := range listAll(cfg, "pokemon-species") {
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, species.Name)
+	}
+	return names, nil
+}
+
+func completeCommands(cfg *config, args ...string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	names := []string{}
+	for name := range getCommands() {
+		names = append(names, name)
+	}
+	return names
+}
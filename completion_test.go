@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Professor-Goo/pokedexcli/internal/pokecache"
+)
+
+func TestCompleteInput(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute)
+	cache.Add(listURL("pokemon-species", 0, bulkPageLimit), []byte(`{"count": 3, "results": [{"name": "pichu"}, {"name": "pikachu"}, {"name": "raichu"}]}`))
+
+	cfg := &config{
+		pokeapiClient: cache,
+		caughtPokemon: map[string]ownedPokemon{"pidgey": {}, "psyduck": {}},
+		knownAreas:    map[string]bool{"canalave-city-area": true, "eterna-city-area": true},
+		lastExplored:  []string{"pidgey", "tentacool"},
+		bag:           map[string]int{"oran-berry": 1},
+	}
+	commands := getCommands()
+
+	cases := []struct {
+		head     string
+		expected []string
+	}{
+		{head: "ex", expected: []string{"exit", "explore"}},
+		{head: "expl", expected: []string{"explore"}},
+		{head: "EVOL", expected: []string{"evolution", "evolve"}},
+		{head: "explore ca", expected: []string{"canalave-city-area"}},
+		{head: "explore canalave-city-area ", expected: []string{}},
+		{head: "catch pi", expected: []string{"pichu", "pidgey", "pikachu"}},
+		{head: "catch ", expected: []string{"pichu", "pidgey", "pikachu", "raichu", "tentacool"}},
+		{head: "inspect p", expected: []string{"pidgey", "psyduck"}},
+		{head: "give psyduck ", expected: []string{"oran-berry"}},
+		{head: "pokedex ", expected: nil},
+		{head: "nosuchcommand ", expected: nil},
+	}
+
+	for _, c := range cases {
+		actual := completeInput(cfg, commands, c.head)
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("for %q expected %v, got %v", c.head, c.expected, actual)
+		}
+	}
+}
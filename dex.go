@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+type dexResult struct {
+	Pokemon       string      `json:"pokemon"`
+	DisplayName   string      `json:"display_name,omitempty"`
+	National      int         `json:"national"`
+	Numbers       []dexNumber `json:"pokedex_numbers"`
+	Genus         string      `json:"genus,omitempty"`
+	FlavorText    string      `json:"flavor_text,omitempty"`
+	FlavorVersion string      `json:"flavor_version,omitempty"`
+	Generation    string      `json:"generation,omitempty"`
+	Habitat       string      `json:"habitat,omitempty"`
+	Color         string      `json:"color,omitempty"`
+	Shape         string      `json:"shape,omitempty"`
+	EggGroups     []string    `json:"egg_groups"`
+	GenderRate    int         `json:"gender_rate"`
+	Baby          bool        `json:"baby"`
+	Legendary     bool        `json:"legendary"`
+	Mythical      bool        `json:"mythical"`
+}
+
+type dexNumber struct {
+	Pokedex string `json:"pokedex"`
+	Number  int    `json:"number"`
+}
+
+func (r dexResult) renderText(w io.Writer) {
+	name := r.Pokemon
+	if r.DisplayName != "" && r.DisplayName != r.Pokemon {
+		name = fmt.Sprintf("%s (%s)", r.DisplayName, r.Pokemon)
+	}
+	fmt.Fprintf(w, "#%03d %s\n", r.National, name)
+	if r.Genus != "" {
+		fmt.Fprintln(w, r.Genus)
+	}
+	switch {
+	case r.Mythical:
+		fmt.Fprintln(w, "Mythical Pokemon")
+	case r.Legendary:
+		fmt.Fprintln(w, "Legendary Pokemon")
+	case r.Baby:
+		fmt.Fprintln(w, "Baby Pokemon")
+	}
+	if r.FlavorText != "" {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%s (%s)\n", r.FlavorText, r.FlavorVersion)
+	}
+
+	fmt.Fprintln(w)
+	if r.Generation != "" {
+		fmt.Fprintf(w, "Generation: %s\n", r.Generation)
+	}
+	if r.Habitat != "" {
+		fmt.Fprintf(w, "Habitat: %s\n", r.Habitat)
+	}
+	if r.Color != "" {
+		fmt.Fprintf(w, "Color: %s\n", r.Color)
+	}
+	if r.Shape != "" {
+		fmt.Fprintf(w, "Shape: %s\n", r.Shape)
+	}
+	if len(r.EggGroups) > 0 {
+		fmt.Fprintf(w, "Egg groups: %s\n", strings.Join(r.EggGroups, ", "))
+	}
+	fmt.Fprintf(w, "Gender: %s\n", genderText(r.GenderRate))
+	if len(r.Numbers) > 0 {
+		fmt.Fprintln(w, "Pokedex numbers:")
+		for _, number := range r.Numbers {
+			fmt.Fprintf(w, "  - %s: %d\n", number.Pokedex, number.Number)
+		}
+	}
+}
+
+// genderText describes PokeAPI's gender rate, the chance of being female in
+// eighths, or -1 for genderless species.
+func genderText(rate int) string {
+	if rate < 0 {
+		return "genderless"
+	}
+	female := float64(rate) * 12.5
+	return fmt.Sprintf("%g%% male, %g%% female", 100-female, female)
+}
+
+func commandDex(cfg *config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: dex <pokemon_name>")
+	}
+
+	pokemon, err := resolvePokemon(cfg, normalizeName(args[0]))
+	if err != nil {
+		return err
+	}
+
+	species, err := getPokemonSpecies(cfg, pokemon.Species.URL)
+	if err != nil {
+		return err
+	}
+
+	return emit(cfg, newDexResult(cfg, pokemon.Name, species))
+}
+
+func newDexResult(cfg *config, pokemonName string, species RespPokemonSpecies) dexResult {
+	result := dexResult{
+		Pokemon:    pokemonName,
+		National:   species.ID,
+		Numbers:    []dexNumber{},
+		Generation: species.Generation.Name,
+		Color:      species.Color.Name,
+		EggGroups:  []string{},
+		GenderRate: species.GenderRate,
+		Baby:       species.IsBaby,
+		Legendary:  species.IsLegendary,
+		Mythical:   species.IsMythical,
+	}
+
+	names := species.Names
+	if i := localized(cfg.language, len(names), func(i int) string { return names[i].Language.Name }); i >= 0 {
+		result.DisplayName = names[i].Name
+	}
+	genera := species.Genera
+	if i := localized(cfg.language, len(genera), func(i int) string { return genera[i].Language.Name }); i >= 0 {
+		result.Genus = genera[i].Genus
+	}
+
+	entries := species.FlavorTextEntries
+	if i := pickFlavorText(cfg, len(entries), func(i int) (string, string) {
+		return entries[i].Language.Name, entries[i].Version.Name
+	}); i >= 0 {
+		result.FlavorText = strings.Join(strings.Fields(entries[i].FlavorText), " ")
+		result.FlavorVersion = entries[i].Version.Name
+	}
+
+	if species.Habitat != nil {
+		result.Habitat = species.Habitat.Name
+	}
+	if species.Shape != nil {
+		result.Shape = species.Shape.Name
+	}
+	for _, group := range species.EggGroups {
+		result.EggGroups = append(result.EggGroups, group.Name)
+	}
+	for _, number := range species.PokedexNumbers {
+		result.Numbers = append(result.Numbers, dexNumber{number.Pokedex.Name, number.EntryNumber})
+	}
+	return result
+}
+
+// pickFlavorText returns the index of the best flavor text entry: the
+// selected language over English, then the selected game version, then the
+// newest entry. It returns -1 if there is no entry in either language.
+func pickFlavorText(cfg *config, n int, entryAt func(i int) (language, version string)) int {
+	score := func(i int) int {
+		language, version := entryAt(i)
+		s := 0
+		switch language {
+		case cfg.language:
+			s = 4
+		case defaultLanguage:
+			s = 2
+		default:
+			return -1
+		}
+		if cfg.gameVersion != "" && version == cfg.gameVersion {
+			s++
+		}
+		return s
+	}
+
+	best := -1
+	for i := 0; i < n; i++ {
+		if s := score(i); s >= 0 && (best < 0 || s >= score(best)) {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,17 @@
+package main
+
+import "testing"
+
+func TestGenderText(t *testing.T) {
+	cases := map[int]string{
+		-1: "genderless",
+		0:  "100% male, 0% female",
+		1:  "87.5% male, 12.5% female",
+		8:  "0% male, 100% female",
+	}
+	for rate, expected := range cases {
+		if actual := genderText(rate); actual != expected {
+			t.Errorf("genderText(%d) = %q, expected %q", rate, actual, expected)
+		}
+	}
+}
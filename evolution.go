@@ -0,0 +1,437 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+type RespPokemonSpecies struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	EvolvesFromSpecies *struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"evolves_from_species"`
+	Varieties []struct {
+		IsDefault bool `json:"is_default"`
+		Pokemon   struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"pokemon"`
+	} `json:"varieties"`
+	PokedexNumbers []struct {
+		EntryNumber int              `json:"entry_number"`
+		Pokedex     namedAPIResource `json:"pokedex"`
+	} `json:"pokedex_numbers"`
+	Genera []struct {
+		Genus    string           `json:"genus"`
+		Language namedAPIResource `json:"language"`
+	} `json:"genera"`
+	FlavorTextEntries []struct {
+		FlavorText string           `json:"flavor_text"`
+		Language   namedAPIResource `json:"language"`
+		Version    namedAPIResource `json:"version"`
+	} `json:"flavor_text_entries"`
+	Names []struct {
+		Name     string           `json:"name"`
+		Language namedAPIResource `json:"language"`
+	} `json:"names"`
+	Habitat     *namedAPIResource  `json:"habitat"`
+	Color       namedAPIResource   `json:"color"`
+	Shape       *namedAPIResource  `json:"shape"`
+	EggGroups   []namedAPIResource `json:"egg_groups"`
+	Generation  namedAPIResource   `json:"generation"`
+	GenderRate  int                `json:"gender_rate"`
+	IsBaby      bool               `json:"is_baby"`
+	IsLegendary bool               `json:"is_legendary"`
+	IsMythical  bool               `json:"is_mythical"`
+}
+
+type RespEvolutionChain struct {
+	ID    int       `json:"id"`
+	Chain chainLink `json:"chain"`
+}
+
+type chainLink struct {
+	Species struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"species"`
+	EvolutionDetails []evolutionDetail `json:"evolution_details"`
+	EvolvesTo        []chainLink       `json:"evolves_to"`
+}
+
+type evolutionDetail struct {
+	Trigger struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"trigger"`
+	Item *struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"item"`
+	HeldItem *struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"held_item"`
+	KnownMove *struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"known_move"`
+	Location *struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"location"`
+	MinLevel     *int   `json:"min_level"`
+	MinHappiness *int   `json:"min_happiness"`
+	MinAffection *int   `json:"min_affection"`
+	TimeOfDay    string `json:"time_of_day"`
+}
+
+type evolutionResult struct {
+	Pokemon string        `json:"pokemon"`
+	Chain   evolutionNode `json:"chain"`
+}
+
+type evolutionNode struct {
+	Species   string          `json:"species"`
+	Condition string          `json:"condition,omitempty"`
+	EvolvesTo []evolutionNode `json:"evolves_to"`
+}
+
+func (r evolutionResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Evolution chain for %s:\n", r.Pokemon)
+	fmt.Fprintln(w, r.Chain.Species)
+	printEvolutions(w, r.Chain, "")
+}
+
+func commandEvolution(cfg *config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: evolution <pokemon_name>")
+	}
+
+	pokemon, err := resolvePokemon(cfg, normalizeName(args[0]))
+	if err != nil {
+		return err
+	}
+
+	chain, err := getChainForPokemon(cfg, pokemon)
+	if err != nil {
+		return err
+	}
+
+	return emit(cfg, evolutionResult{
+		Pokemon: pokemon.Name,
+		Chain:   newEvolutionNode(chain.Chain),
+	})
+}
+
+func newEvolutionNode(link chainLink) evolutionNode {
+	node := evolutionNode{
+		Species:   link.Species.Name,
+		EvolvesTo: []evolutionNode{},
+	}
+	if len(link.EvolutionDetails) > 0 {
+		node.Condition = describeEvolution(link.EvolutionDetails)
+	}
+	for _, next := range link.EvolvesTo {
+		node.EvolvesTo = append(node.EvolvesTo, newEvolutionNode(next))
+	}
+	return node
+}
+
+func printEvolutions(w io.Writer, node evolutionNode, indent string) {
+	for i, next := range node.EvolvesTo {
+		branch, childIndent := "├── ", "│   "
+		if i == len(node.EvolvesTo)-1 {
+			branch, childIndent = "└── ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s (%s)\n", indent, branch, next.Species, next.Condition)
+		printEvolutions(w, next, indent+childIndent)
+	}
+}
+
+func describeEvolution(details []evolutionDetail) string {
+	descriptions := []string{}
+	for _, detail := range details {
+		descriptions = append(descriptions, describeEvolutionDetail(detail))
+	}
+	if len(descriptions) == 0 {
+		return "unknown"
+	}
+	return strings.Join(descriptions, " or ")
+}
+
+func describeEvolutionDetail(detail evolutionDetail) string {
+	parts := []string{}
+	switch detail.Trigger.Name {
+	case "level-up":
+		switch {
+		case detail.MinLevel != nil:
+			parts = append(parts, fmt.Sprintf("level %d", *detail.MinLevel))
+		case detail.MinHappiness != nil:
+			parts = append(parts, fmt.Sprintf("friendship %d", *detail.MinHappiness))
+		case detail.MinAffection != nil:
+			parts = append(parts, fmt.Sprintf("affection %d", *detail.MinAffection))
+		default:
+			parts = append(parts, "level up")
+		}
+	case "use-item":
+		if detail.Item != nil {
+			parts = append(parts, "use "+detail.Item.Name)
+		} else {
+			parts = append(parts, "use item")
+		}
+	case "trade":
+		parts = append(parts, "trade")
+	default:
+		parts = append(parts, detail.Trigger.Name)
+	}
+
+	if detail.HeldItem != nil {
+		parts = append(parts, "holding "+detail.HeldItem.Name)
+	}
+	if detail.KnownMove != nil {
+		parts = append(parts, "knowing "+detail.KnownMove.Name)
+	}
+	if detail.Location != nil {
+		parts = append(parts, "at "+detail.Location.Name)
+	}
+	if detail.TimeOfDay != "" {
+		parts = append(parts, "during "+detail.TimeOfDay)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+type evolveResult struct {
+	Pokemon     string            `json:"pokemon"`
+	EvolvedInto string            `json:"evolved_into,omitempty"`
+	Options     []evolutionOption `json:"options"`
+}
+
+type evolutionOption struct {
+	Species   string `json:"species"`
+	Condition string `json:"condition"`
+}
+
+func (r evolveResult) renderText(w io.Writer) {
+	switch {
+	case r.EvolvedInto != "":
+		fmt.Fprintf(w, "What? %s is evolving!\n", r.Pokemon)
+		fmt.Fprintf(w, "Congratulations! Your %s evolved into %s!\n", r.Pokemon, r.EvolvedInto)
+	case len(r.Options) == 0:
+		fmt.Fprintf(w, "%s does not evolve\n", r.Pokemon)
+	default:
+		fmt.Fprintf(w, "%s can't evolve yet:\n", r.Pokemon)
+		for _, option := range r.Options {
+			fmt.Fprintf(w, " - %s (%s)\n", option.Species, option.Condition)
+		}
+	}
+}
+
+func commandEvolve(cfg *config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: evolve <pokemon_name>")
+	}
+
+	pokemonName := normalizeName(args[0])
+
+	owned, exists := cfg.caughtPokemon[pokemonName]
+	if !exists {
+		return emit(cfg, messageResult{"you have not caught that pokemon"})
+	}
+
+	chain, err := getChainForPokemon(cfg, owned.RespPokemon)
+	if err != nil {
+		return err
+	}
+
+	link, ok := findChainLink(chain.Chain, owned.Species.Name)
+	result := evolveResult{Pokemon: pokemonName, Options: []evolutionOption{}}
+	if !ok {
+		return emit(cfg, result)
+	}
+	for _, next := range link.EvolvesTo {
+		result.Options = append(result.Options, evolutionOption{next.Species.Name, describeEvolution(next.EvolutionDetails)})
+	}
+
+	for _, next := range link.EvolvesTo {
+		for _, detail := range next.EvolutionDetails {
+			if !canEvolve(owned, detail) {
+				continue
+			}
+
+			species, err := getPokemonSpecies(cfg, next.Species.URL)
+			if err != nil {
+				return err
+			}
+			evolved, err := getPokemon(cfg, evolvedVariety(species, owned.RespPokemon))
+			if err != nil {
+				return err
+			}
+			if _, exists := cfg.caughtPokemon[evolved.Name]; exists {
+				return fmt.Errorf("you already have a %s", evolved.Name)
+			}
+
+			if detail.Trigger.Name == "use-item" || detail.HeldItem != nil {
+				owned.HeldItem = ""
+			}
+			owned.History = append(owned.History, fmt.Sprintf("evolved from %s at level %d", owned.Name, owned.Level))
+			owned.Ability = abilityAfterEvolution(owned.Ability, owned.RespPokemon, evolved)
+			owned.RespPokemon = evolved
+
+			delete(cfg.caughtPokemon, pokemonName)
+			cfg.caughtPokemon[evolved.Name] = owned
+			recordCaught(cfg, resourceID(evolved.Species.URL), evolved.Species.Name)
+
+			result.EvolvedInto = evolved.Name
+			return emit(cfg, result)
+		}
+	}
+
+	return emit(cfg, result)
+}
+
+func canEvolve(owned ownedPokemon, detail evolutionDetail) bool {
+	if detail.MinHappiness != nil || detail.MinAffection != nil || detail.KnownMove != nil ||
+		detail.Location != nil || detail.TimeOfDay != "" {
+		return false
+	}
+	if detail.HeldItem != nil && owned.HeldItem != detail.HeldItem.Name {
+		return false
+	}
+
+	switch detail.Trigger.Name {
+	case "level-up":
+		return detail.MinLevel != nil && owned.Level >= *detail.MinLevel
+	case "use-item":
+		return detail.Item != nil && owned.HeldItem == detail.Item.Name
+	default:
+		return false
+	}
+}
+
+func findChainLink(link chainLink, speciesName string) (chainLink, bool) {
+	if link.Species.Name == speciesName {
+		return link, true
+	}
+	for _, next := range link.EvolvesTo {
+		if found, ok := findChainLink(next, speciesName); ok {
+			return found, true
+		}
+	}
+	return chainLink{}, false
+}
+
+func defaultVariety(species RespPokemonSpecies) string {
+	for _, variety := range species.Varieties {
+		if variety.IsDefault {
+			return variety.Pokemon.Name
+		}
+	}
+	return species.Name
+}
+
+// evolvedVariety picks the variety of the species a pokemon evolves into that
+// keeps its form, like ninetales-alola for vulpix-alola, or the default one
+// if the species has no such form.
+func evolvedVariety(species RespPokemonSpecies, pokemon RespPokemon) string {
+	suffix, ok := strings.CutPrefix(pokemon.Name, pokemon.Species.Name)
+	if pokemon.IsDefault || !ok || suffix == "" {
+		return defaultVariety(species)
+	}
+	for _, variety := range species.Varieties {
+		if variety.Pokemon.Name == species.Name+suffix {
+			return variety.Pokemon.Name
+		}
+	}
+	return defaultVariety(species)
+}
+
+func getChainForPokemon(cfg *config, pokemon RespPokemon) (RespEvolutionChain, error) {
+	species, err := getPokemonSpecies(cfg, pokemon.Species.URL)
+	if err != nil {
+		return RespEvolutionChain{}, err
+	}
+	return getEvolutionChain(cfg, species.EvolutionChain.URL)
+}
+
+func getPokemonSpecies(cfg *config, url string) (RespPokemonSpecies, error) {
+	if val, ok := cfg.pokeapiClient.Get(url); ok {
+		var speciesResp RespPokemonSpecies
+		err := json.Unmarshal(val, &speciesResp)
+		return speciesResp, err
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return RespPokemonSpecies{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return RespPokemonSpecies{}, &notFoundError{Kind: "species", Name: path.Base(url)}
+	}
+	if res.StatusCode > 299 {
+		return RespPokemonSpecies{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return RespPokemonSpecies{}, err
+	}
+
+	cfg.pokeapiClient.Add(url, body)
+
+	var speciesResp RespPokemonSpecies
+	err = json.Unmarshal(body, &speciesResp)
+	if err != nil {
+		return RespPokemonSpecies{}, err
+	}
+
+	return speciesResp, nil
+}
+
+func getEvolutionChain(cfg *config, url string) (RespEvolutionChain, error) {
+	if val, ok := cfg.pokeapiClient.Get(url); ok {
+		var chainResp RespEvolutionChain
+		err := json.Unmarshal(val, &chainResp)
+		return chainResp, err
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return RespEvolutionChain{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return RespEvolutionChain{}, &notFoundError{Kind: "evolution chain", Name: path.Base(url)}
+	}
+	if res.StatusCode > 299 {
+		return RespEvolutionChain{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return RespEvolutionChain{}, err
+	}
+
+	cfg.pokeapiClient.Add(url, body)
+
+	var chainResp RespEvolutionChain
+	err = json.Unmarshal(body, &chainResp)
+	if err != nil {
+		return RespEvolutionChain{}, err
+	}
+
+	return chainResp, nil
+}
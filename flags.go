@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// parseFlags separates --name options from positional arguments. Options in
+// valueFlags take a value, either as the next argument or after "="; options
+// in boolFlags take none and are recorded as "true". Everything after "--" is
+// positional.
+func parseFlags(args []string, valueFlags, boolFlags []string) (map[string]string, []string, error) {
+	flags := map[string]string{}
+	positional := []string{}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		switch {
+		case slices.Contains(boolFlags, name):
+			if hasValue {
+				return nil, nil, fmt.Errorf("--%s does not take a value", name)
+			}
+			flags[name] = "true"
+		case slices.Contains(valueFlags, name):
+			if !hasValue {
+				if i+1 == len(args) {
+					return nil, nil, fmt.Errorf("--%s needs a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			flags[name] = value
+		default:
+			return nil, nil, fmt.Errorf("unknown flag --%s", name)
+		}
+	}
+
+	return flags, positional, nil
+}
+
+// intFlag returns the named flag as a positive number, or def when it was not
+// given.
+func intFlag(flags map[string]string, name string, def int) (int, error) {
+	value, ok := flags[name]
+	if !ok {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("--%s must be a positive number", name)
+	}
+	return n, nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseFlags(t *testing.T) {
+	flags, positional, err := parseFlags(
+		[]string{"pikachu", "--version", "diamond", "--shiny", "--page=2", "--", "--literal"},
+		[]string{"version", "page"}, []string{"shiny"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if flags["version"] != "diamond" || flags["page"] != "2" || flags["shiny"] != "true" {
+		t.Errorf("flags = %v", flags)
+	}
+	if !slices.Equal(positional, []string{"pikachu", "--literal"}) {
+		t.Errorf("positional = %q", positional)
+	}
+
+	for _, args := range [][]string{{"--bogus"}, {"--page"}, {"--shiny=yes"}} {
+		if _, _, err := parseFlags(args, []string{"page"}, []string{"shiny"}); err == nil {
+			t.Errorf("parseFlags(%q) should fail", args)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type RespPokemonForm struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	FormName  string `json:"form_name"`
+	IsDefault bool   `json:"is_default"`
+	Pokemon   struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"pokemon"`
+}
+
+func resolvePokemon(cfg *config, name string) (RespPokemon, error) {
+	pokemon, err := getPokemon(cfg, name)
+	if err == nil {
+		return pokemon, nil
+	}
+
+	if form, formErr := getPokemonForm(cfg, name); formErr == nil {
+		return getPokemon(cfg, form.Pokemon.Name)
+	}
+
+	if species, speciesErr := getPokemonSpecies(cfg, "https://pokeapi.co/api/v2/pokemon-species/"+name); speciesErr == nil {
+		return getPokemon(cfg, defaultVariety(species))
+	}
+
+	return RespPokemon{}, suggestMatches(cfg, err)
+}
+
+func getPokemonForm(cfg *config, formName string) (RespPokemonForm, error) {
+	url := "https://pokeapi.co/api/v2/pokemon-form/" + formName
+
+	if val, ok := cfg.pokeapiClient.Get(url); ok {
+		var formResp RespPokemonForm
+		err := json.Unmarshal(val, &formResp)
+		return formResp, err
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return RespPokemonForm{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		return RespPokemonForm{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return RespPokemonForm{}, err
+	}
+
+	cfg.pokeapiClient.Add(url, body)
+
+	var formResp RespPokemonForm
+	err = json.Unmarshal(body, &formResp)
+	if err != nil {
+		return RespPokemonForm{}, err
+	}
+
+	return formResp, nil
+}
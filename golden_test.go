@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Professor-Goo/pokedexcli/internal/pokecache"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// newTestConfig returns a config whose cache is pre-filled with the PokeAPI
+// responses in testdata/pokeapi.json, so commands never reach the network.
+// Everything the commands write ends up in out.
+func newTestConfig(t *testing.T, out *bytes.Buffer) *config {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "pokeapi.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var responses map[string]json.RawMessage
+	if err := json.Unmarshal(data, &responses); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := pokecache.NewCache(time.Hour)
+	for url, body := range responses {
+		cache.Add(url, body)
+	}
+
+	cfg := &config{
+		pokeapiClient: cache,
+		caughtPokemon: make(map[string]ownedPokemon),
+		pokedex:       make(map[int]dexRecord),
+		wildLevels:    make(map[string]levelRange),
+		shinyOdds:     defaultShinyOdds,
+		language:      defaultLanguage,
+		bag:           make(map[string]int),
+		knownAreas:    make(map[string]bool),
+		aliases:       make(map[string]string),
+		outputFormat:  "text",
+		out:           out,
+		errOut:        out,
+	}
+	setSeed(cfg, 1)
+	return cfg
+}
+
+func TestCommandOutput(t *testing.T) {
+	cases := []struct {
+		name  string
+		input []string
+	}{
+		{name: "help", input: []string{"help"}},
+		{name: "help_catch", input: []string{"help catch"}},
+		{name: "exit", input: []string{"exit"}},
+		{name: "map", input: []string{"map", "map"}},
+		{name: "map_paging", input: []string{"map --limit 3", "map", "map", "mapb", "map last", "map first", "map --page 2", "map --page=3", "map --limit 20", "map --page x", "map middle"}},
+		{name: "list", input: []string{"list location-area --limit 3", "map", "list location-area prev", "list location-area sideways", "list bogus"}},
+		{name: "search", input: []string{"search char", "search charmandr --kind pokemon", "search city --kind area --limit 2", "search thunder", "search zzzzzz", "search pika --kind berry"}},
+		{name: "regions", input: []string{"regions", "region Sinnoh", "location pastoria-city", "location twinleaf-town", "map --region sinnoh --limit 3", "map", "map", "mapb", "map --region any", "region"}},
+		{name: "mapb_first_page", input: []string{"mapb"}},
+		{name: "explore", input: []string{"explore pastoria-city-area"}},
+		{name: "explore_usage", input: []string{"explore"}},
+		{name: "explore_versions", input: []string{"explore Pastoria-City-Area --sort rarity", "explore pastoria-city-area --version pearl", "set version platinum", "explore pastoria-city-area", "explore pastoria-city-area --version any --sort name", "explore pastoria-city-area --sort level"}},
+		{name: "where", input: []string{"where pikachu", "where Pikachu --version platinum", "where magikarp", "where gyarados", "set version pearl", "where magikarp", "where"}},
+		{name: "dex", input: []string{"dex pikachu", "set version diamond", "dex pikachu", "set language fr", "dex Pikachu", "dex magikarp", "set language xx", "dex"}},
+		{name: "catch", input: []string{"seed 3", "explore pastoria-city-area", "catch pikachu"}},
+		{name: "catch_duplicate", input: []string{"seed 3", "catch pikachu", `nickname pikachu "Sparky"`, "catch pikachu", "inspect pikachu", "bag"}},
+		{name: "catch_escaped", input: []string{"seed 1", "catch pikachu"}},
+		{name: "inspect", input: []string{"seed 3", "explore pastoria-city-area", "catch pikachu", "inspect pikachu"}},
+		{name: "inspect_not_caught", input: []string{"inspect pikachu"}},
+		{name: "nickname", input: []string{"seed 3", "catch pikachu", `nickname Pikachu "Sir Fluffy"`, "inspect PIKACHU", "nickname pikachu", `nickname pikachu "unterminated`}},
+		{name: "alias_quoted", input: []string{"seed 3", "catch pikachu", `alias nn = nickname pikachu "Sir Fluffy"`, "nn", "alias rename = nickname $1 'Lord Sparky'", "rename pikachu", "alias"}},
+		{name: "alias", input: []string{"alias", `alias hunt = "explore $1; catch $2"`, "seed 3", "hunt pastoria-city-area pikachu", "i pikachu", "alias", "alias loop = loop", "loop", "alias map = n", "unalias loop", "unalias loop"}},
+		{name: "pokedex", input: []string{"pokedex", "seed 3", "catch pikachu", "pokedex"}},
+		{name: "pokedex_sorted", input: []string{"explore pastoria-city-area", "seed 3", "catch pikachu", "set shiny-odds 1", "catch magikarp", "pokedex", "pokedex --sort level", "pokedex --type water", "pokedex --gen 4", "pokedex --limit 1 --page 2", "pokedex --page 3", "pokedex --shiny --sort name", "pokedex --sort bst --missing"}},
+		{name: "pokedex_missing", input: []string{"explore pastoria-city-area", "seed 3", "catch pikachu", "pokedex --missing", "pokedex --dex original-sinnoh --missing"}},
+		{name: "compare", input: []string{"compare pikachu magikarp gyarados", "compare pikachu raichu", "compare pikachu"}},
+		{name: "evolution", input: []string{"evolution pikachu"}},
+		{name: "evolve", input: []string{"seed 3", "explore pastoria-city-area", "catch magikarp", "evolve magikarp", "inspect gyarados"}},
+		{name: "evolve_form", input: []string{"seed 2", "catch vulpix-alola", "evolve vulpix-alola", "inspect ninetales-alola"}},
+		{name: "evolve_not_ready", input: []string{"seed 3", "catch pikachu", "evolve pikachu"}},
+		{name: "ability", input: []string{"ability static"}},
+		{name: "set", input: []string{"set", "set shiny-odds 1", "set version pearl", "set output yaml", "set", "set color red"}},
+		{name: "bag", input: []string{"bag", "seed 3", "catch pikachu", "take pikachu", "take pikachu", "bag", "give pikachu light-ball", "bag"}},
+		{name: "seed", input: []string{"seed 42", "seed", "seed abc"}},
+		{name: "unknown", input: []string{"bogus"}},
+		{name: "json", input: []string{"set output json", "seed 3", "explore pastoria-city-area", "catch pikachu", "inspect pikachu", "bogus"}},
+		{name: "yaml", input: []string{"set output yaml", "evolution pikachu", "pokedex"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var out bytes.Buffer
+			cfg := newTestConfig(t, &out)
+			commands := getCommands()
+
+			for _, input := range c.input {
+				out.WriteString("Pokedex > " + input + "\n")
+				err := runCommand(cfg, commands, input)
+				if err == errExit {
+					shutdown(cfg)
+					break
+				}
+				if err != nil {
+					reportError(cfg, err)
+				}
+			}
+
+			golden := filepath.Join("testdata", "golden", c.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, out.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("missing golden file, run go test -update: %v", err)
+			}
+			if !bytes.Equal(out.Bytes(), expected) {
+				t.Errorf("output differs from %s\n--- expected\n%s\n--- got\n%s", golden, expected, out.Bytes())
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	categoryExploring = "Exploring"
+	categoryItems     = "Items"
+	categoryPokemon   = "Pokemon"
+	categorySession   = "Session"
+)
+
+type helpResult struct {
+	Categories []helpCategory `json:"categories"`
+}
+
+type helpCategory struct {
+	Name     string      `json:"name"`
+	Commands []helpEntry `json:"commands"`
+}
+
+type helpEntry struct {
+	Name        string   `json:"name"`
+	Usage       string   `json:"usage"`
+	Description string   `json:"description"`
+	Aliases     []string `json:"aliases,omitempty"`
+}
+
+func (r helpResult) renderText(w io.Writer) {
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Welcome to the Pokedex!")
+	fmt.Fprintln(w, "Usage:")
+	for _, category := range r.Categories {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "%s:\n", category.Name)
+		for _, entry := range category.Commands {
+			usage := entry.Usage
+			if len(entry.Aliases) > 0 {
+				usage += " (alias " + strings.Join(entry.Aliases, ", ") + ")"
+			}
+			fmt.Fprintf(w, "  %s: %s\n", usage, entry.Description)
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'help <command>' for details on a command.")
+	fmt.Fprintln(w)
+}
+
+type commandHelpResult struct {
+	Name        string         `json:"name"`
+	Category    string         `json:"category"`
+	Usage       string         `json:"usage"`
+	Description string         `json:"description"`
+	Aliases     []string       `json:"aliases"`
+	Arguments   []helpArgument `json:"arguments"`
+	Examples    []string       `json:"examples"`
+	program     string
+}
+
+type helpArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (r commandHelpResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s%s\n", r.program, r.Usage)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, r.Description)
+	if len(r.Aliases) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "Aliases: %s\n", strings.Join(r.Aliases, ", "))
+	}
+	if len(r.Arguments) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Arguments:")
+		width := 0
+		for _, arg := range r.Arguments {
+			width = max(width, len(arg.Name))
+		}
+		for _, arg := range r.Arguments {
+			fmt.Fprintf(w, "  %-*s  %s\n", width, arg.Name, arg.Description)
+		}
+	}
+	if len(r.Examples) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Examples:")
+		for _, example := range r.Examples {
+			fmt.Fprintf(w, "  %s%s\n", r.program, example)
+		}
+	}
+}
+
+func commandHelp(cfg *config, args ...string) error {
+	commands := getCommands()
+
+	if len(args) > 1 {
+		return fmt.Errorf("usage: help [<command>]")
+	}
+	if len(args) == 1 {
+		command, exists := commands[normalizeName(args[0])]
+		if !exists {
+			return fmt.Errorf("unknown command %q", args[0])
+		}
+		return emit(cfg, newCommandHelpResult(command, ""))
+	}
+
+	return emit(cfg, newHelpResult(commands))
+}
+
+func newHelpResult(commands map[string]cliCommand) helpResult {
+	byCategory := map[string][]helpEntry{}
+	for name, command := range commands {
+		if name != command.name {
+			continue
+		}
+		byCategory[command.category] = append(byCategory[command.category], helpEntry{
+			Name:        command.name,
+			Usage:       command.usage,
+			Description: command.description,
+			Aliases:     command.aliases,
+		})
+	}
+
+	result := helpResult{Categories: []helpCategory{}}
+	for name, entries := range byCategory {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		result.Categories = append(result.Categories, helpCategory{Name: name, Commands: entries})
+	}
+	sort.Slice(result.Categories, func(i, j int) bool {
+		return result.Categories[i].Name < result.Categories[j].Name
+	})
+	return result
+}
+
+// newCommandHelpResult builds the detailed page for one command. program is
+// prepended to the usage and examples when run from the shell.
+func newCommandHelpResult(command cliCommand, program string) commandHelpResult {
+	result := commandHelpResult{
+		Name:        command.name,
+		Category:    command.category,
+		Usage:       command.usage,
+		Description: command.description,
+		Aliases:     append([]string{}, command.aliases...),
+		Arguments:   []helpArgument{},
+		Examples:    []string{},
+		program:     program,
+	}
+	for _, arg := range command.args {
+		result.Arguments = append(result.Arguments, helpArgument{arg.name, arg.description})
+	}
+	result.Examples = append(result.Examples, command.examples...)
+	return result
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCommandsHaveHelp(t *testing.T) {
+	for name, command := range getCommands() {
+		if command.name != name && !slices.Contains(command.aliases, name) {
+			t.Errorf("command %q is registered as %q", command.name, name)
+		}
+		if command.usage == "" || command.category == "" || command.description == "" {
+			t.Errorf("command %q is missing usage, category or description", name)
+		}
+		if name != command.name {
+			continue
+		}
+		if !strings.HasPrefix(command.usage, name) {
+			t.Errorf("usage of %q does not start with its name: %q", name, command.usage)
+		}
+		for _, example := range command.examples {
+			if !strings.HasPrefix(example, name) {
+				t.Errorf("example for %q does not start with its name: %q", name, example)
+			}
+		}
+	}
+}
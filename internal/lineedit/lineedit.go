@@ -0,0 +1,454 @@
+package lineedit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+const maxHistory = 1000
+
+const (
+	keyCtrlA     = 0x01
+	keyCtrlB     = 0x02
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyCtrlE     = 0x05
+	keyCtrlF     = 0x06
+	keyCtrlG     = 0x07
+	keyCtrlH     = 0x08
+	keyTab       = 0x09
+	keyCtrlK     = 0x0b
+	keyCtrlL     = 0x0c
+	keyEnter     = 0x0d
+	keyCtrlN     = 0x0e
+	keyCtrlP     = 0x10
+	keyCtrlR     = 0x12
+	keyCtrlU     = 0x15
+	keyCtrlW     = 0x17
+	keyEscape    = 0x1b
+	keyBackspace = 0x7f
+)
+
+const (
+	keyUnknown = -(iota + 1)
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyDelete
+)
+
+type Editor struct {
+	Prompt string
+
+	// Complete is called when Tab is pressed with the text before the cursor.
+	// It returns the candidates for the word being typed.
+	Complete func(head string) []string
+
+	in       *bufio.Reader
+	out      io.Writer
+	fd       int
+	history  []string
+	histFile *os.File
+}
+
+// New returns an Editor reading keys from in and drawing to out. When in is
+// a terminal it is switched to raw mode for the duration of each ReadLine.
+func New(in io.Reader, out io.Writer) *Editor {
+	fd := -1
+	if f, ok := in.(*os.File); ok && IsTerminal(int(f.Fd())) {
+		fd = int(f.Fd())
+	}
+	return &Editor{
+		in:  bufio.NewReader(in),
+		out: out,
+		fd:  fd,
+	}
+}
+
+// LoadHistory reads previous entries from path and appends new entries to it
+// as they are entered.
+func (e *Editor) LoadHistory(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			e.history = append(e.history, line)
+		}
+	}
+	if len(e.history) > maxHistory {
+		e.history = e.history[len(e.history)-maxHistory:]
+		// Rewrite the file with only what is kept so it doesn't grow forever.
+		if err := os.WriteFile(path, []byte(strings.Join(e.history, "\n")+"\n"), 0o600); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	e.histFile = f
+	return nil
+}
+
+func (e *Editor) AddHistory(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if len(e.history) > 0 && e.history[len(e.history)-1] == line {
+		return
+	}
+
+	e.history = append(e.history, line)
+	if len(e.history) > maxHistory {
+		e.history = e.history[1:]
+	}
+	if e.histFile != nil {
+		fmt.Fprintln(e.histFile, line)
+	}
+}
+
+func (e *Editor) History() []string {
+	return e.history
+}
+
+func (e *Editor) Close() error {
+	if e.histFile == nil {
+		return nil
+	}
+	err := e.histFile.Close()
+	e.histFile = nil
+	return err
+}
+
+// ReadLine reads one line of input, returning io.EOF when Ctrl-D is pressed
+// on an empty line. Entered lines are added to the history.
+func (e *Editor) ReadLine() (string, error) {
+	if e.fd >= 0 {
+		restore, err := makeRaw(e.fd)
+		if err != nil {
+			return "", err
+		}
+		defer restore()
+	}
+
+	s := &state{editor: e, histIndex: len(e.history)}
+	s.refresh()
+
+	for {
+		key, err := e.readKey()
+		if err != nil {
+			if err == io.EOF && len(s.line) > 0 {
+				return e.submit(s), nil
+			}
+			return "", err
+		}
+
+		if s.searching {
+			if done := s.handleSearchKey(key); done {
+				return e.submit(s), nil
+			}
+			continue
+		}
+
+		switch key {
+		case keyEnter, '\n':
+			return e.submit(s), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			s.line = s.line[:0]
+			s.pos = 0
+			s.histIndex = len(e.history)
+		case keyCtrlD:
+			if len(s.line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			s.deleteForward()
+		case keyCtrlA, keyHome:
+			s.pos = 0
+		case keyCtrlE, keyEnd:
+			s.pos = len(s.line)
+		case keyCtrlB, keyLeft:
+			if s.pos > 0 {
+				s.pos--
+			}
+		case keyCtrlF, keyRight:
+			if s.pos < len(s.line) {
+				s.pos++
+			}
+		case keyBackspace, keyCtrlH:
+			if s.pos > 0 {
+				s.line = append(s.line[:s.pos-1], s.line[s.pos:]...)
+				s.pos--
+			}
+		case keyDelete:
+			s.deleteForward()
+		case keyCtrlK:
+			s.line = s.line[:s.pos]
+		case keyCtrlU:
+			s.line = append(s.line[:0], s.line[s.pos:]...)
+			s.pos = 0
+		case keyCtrlW:
+			start := s.pos
+			for start > 0 && unicode.IsSpace(s.line[start-1]) {
+				start--
+			}
+			for start > 0 && !unicode.IsSpace(s.line[start-1]) {
+				start--
+			}
+			s.line = append(s.line[:start], s.line[s.pos:]...)
+			s.pos = start
+		case keyCtrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case keyCtrlP, keyUp:
+			s.historyMove(-1)
+		case keyCtrlN, keyDown:
+			s.historyMove(1)
+		case keyCtrlR:
+			s.startSearch()
+		case keyTab:
+			s.complete()
+		default:
+			if key >= 0 && unicode.IsPrint(rune(key)) {
+				s.insert(rune(key))
+			}
+		}
+		s.refresh()
+	}
+}
+
+func (e *Editor) submit(s *state) string {
+	fmt.Fprint(e.out, "\r\n")
+	line := string(s.line)
+	e.AddHistory(line)
+	return line
+}
+
+func (e *Editor) readKey() (int, error) {
+	r, _, err := e.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if r != keyEscape {
+		return int(r), nil
+	}
+
+	// Only consume the rest of an escape sequence if it is already buffered,
+	// so a lone Escape key press doesn't block.
+	if e.in.Buffered() == 0 {
+		return keyEscape, nil
+	}
+	next, _, err := e.in.ReadRune()
+	if err != nil {
+		return 0, err
+	}
+	if next != '[' && next != 'O' {
+		return keyUnknown, nil
+	}
+
+	seq := []rune{}
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		seq = append(seq, r)
+		if r >= 0x40 && r <= 0x7e {
+			break
+		}
+	}
+
+	switch string(seq) {
+	case "A":
+		return keyUp, nil
+	case "B":
+		return keyDown, nil
+	case "C":
+		return keyRight, nil
+	case "D":
+		return keyLeft, nil
+	case "H", "1~", "7~":
+		return keyHome, nil
+	case "F", "4~", "8~":
+		return keyEnd, nil
+	case "3~":
+		return keyDelete, nil
+	default:
+		return keyUnknown, nil
+	}
+}
+
+type state struct {
+	editor    *Editor
+	line      []rune
+	pos       int
+	histIndex int
+	pending   []rune
+
+	searching   bool
+	failed      bool
+	query       []rune
+	matchIndex  int
+	searchSaved []rune
+}
+
+func (s *state) refresh() {
+	out := s.editor.out
+	if s.searching {
+		match := ""
+		if s.matchIndex >= 0 && s.matchIndex < len(s.editor.history) {
+			match = s.editor.history[s.matchIndex]
+		}
+		label := "reverse-i-search"
+		if s.failed {
+			label = "failed " + label
+		}
+		fmt.Fprintf(out, "\r(%s)`%s': %s\x1b[K", label, string(s.query), match)
+		return
+	}
+
+	fmt.Fprintf(out, "\r%s%s\x1b[K", s.editor.Prompt, string(s.line))
+	if back := len(s.line) - s.pos; back > 0 {
+		fmt.Fprintf(out, "\x1b[%dD", back)
+	}
+}
+
+func (s *state) insert(r rune) {
+	s.line = append(s.line, 0)
+	copy(s.line[s.pos+1:], s.line[s.pos:])
+	s.line[s.pos] = r
+	s.pos++
+}
+
+func (s *state) deleteForward() {
+	if s.pos < len(s.line) {
+		s.line = append(s.line[:s.pos], s.line[s.pos+1:]...)
+	}
+}
+
+func (s *state) historyMove(delta int) {
+	history := s.editor.history
+	next := s.histIndex + delta
+	if next < 0 || next > len(history) {
+		return
+	}
+
+	if s.histIndex == len(history) {
+		s.pending = append([]rune(nil), s.line...)
+	}
+	s.histIndex = next
+
+	if next == len(history) {
+		s.line = append([]rune(nil), s.pending...)
+	} else {
+		s.line = []rune(history[next])
+	}
+	s.pos = len(s.line)
+}
+
+func (s *state) complete() {
+	if s.editor.Complete == nil {
+		return
+	}
+
+	head := string(s.line[:s.pos])
+	wordStart := strings.LastIndexFunc(head, unicode.IsSpace) + 1
+	word := head[wordStart:]
+
+	candidates := s.editor.Complete(head)
+	if len(candidates) == 0 {
+		return
+	}
+
+	replacement := commonPrefix(candidates)
+	if len(candidates) == 1 {
+		replacement += " "
+	}
+
+	if replacement == word {
+		fmt.Fprint(s.editor.out, "\r\n"+strings.Join(candidates, "  ")+"\r\n")
+		return
+	}
+
+	tail := s.line[s.pos:]
+	line := []rune(head[:wordStart] + replacement)
+	s.pos = len(line)
+	s.line = append(line, tail...)
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+func (s *state) startSearch() {
+	s.searching = true
+	s.failed = false
+	s.query = s.query[:0]
+	s.matchIndex = len(s.editor.history)
+	s.searchSaved = append([]rune(nil), s.line...)
+}
+
+func (s *state) search(from int) {
+	query := string(s.query)
+	for i := min(from, len(s.editor.history)-1); i >= 0; i-- {
+		if strings.Contains(s.editor.history[i], query) {
+			s.matchIndex = i
+			s.failed = false
+			return
+		}
+	}
+	s.failed = true
+}
+
+// handleSearchKey processes a key while in reverse search mode. It reports
+// true when the matched line should be submitted.
+func (s *state) handleSearchKey(key int) bool {
+	switch {
+	case key == keyCtrlR:
+		s.search(s.matchIndex - 1)
+	case key == keyBackspace || key == keyCtrlH:
+		if len(s.query) > 0 {
+			s.query = s.query[:len(s.query)-1]
+			s.search(len(s.editor.history) - 1)
+		}
+	case key == keyCtrlG || key == keyCtrlC || key == keyEscape:
+		s.searching = false
+		s.line = s.searchSaved
+		s.pos = len(s.line)
+	case key >= 0 && unicode.IsPrint(rune(key)):
+		s.query = append(s.query, rune(key))
+		s.search(s.matchIndex)
+	default:
+		s.acceptMatch()
+		if key == keyEnter || key == '\n' {
+			return true
+		}
+	}
+	s.refresh()
+	return false
+}
+
+func (s *state) acceptMatch() {
+	s.searching = false
+	if s.matchIndex < len(s.editor.history) {
+		s.line = []rune(s.editor.history[s.matchIndex])
+		s.histIndex = s.matchIndex
+	}
+	s.pos = len(s.line)
+}
@@ -0,0 +1,211 @@
+package lineedit
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadLine(t *testing.T) {
+	cases := []struct {
+		name     string
+		history  []string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "plain line",
+			input:    "map\r",
+			expected: []string{"map"},
+		},
+		{
+			name:     "cursor movement",
+			input:    "acth\x1b[D\x1b[D\x1b[Da\x1b[C\x1b[Cc\r",
+			expected: []string{"aactch"},
+		},
+		{
+			name:     "home, end and delete",
+			input:    "xcatch\x01\x1b[3~\x05!\r",
+			expected: []string{"catch!"},
+		},
+		{
+			name:     "backspace and kill",
+			input:    "explorx\x7fe area\x17\x15map\r",
+			expected: []string{"map"},
+		},
+		{
+			name:     "history up and down",
+			history:  []string{"explore pastoria-city-area", "catch pikachu"},
+			input:    "\x1b[A\x1b[A\x1b[B\r",
+			expected: []string{"catch pikachu"},
+		},
+		{
+			name:     "history keeps the pending line",
+			history:  []string{"map"},
+			input:    "pok\x1b[A\x1b[Bedex\r",
+			expected: []string{"pokedex"},
+		},
+		{
+			name:     "reverse search",
+			history:  []string{"explore pastoria-city-area", "catch pikachu", "map"},
+			input:    "\x12pas\r",
+			expected: []string{"explore pastoria-city-area"},
+		},
+		{
+			name:     "reverse search older match",
+			history:  []string{"catch pidgey", "catch pikachu", "map"},
+			input:    "\x12catch\x12\r",
+			expected: []string{"catch pidgey"},
+		},
+		{
+			name:     "reverse search cancel",
+			history:  []string{"catch pikachu"},
+			input:    "map\x12catch\x07\r",
+			expected: []string{"map"},
+		},
+		{
+			name:     "ctrl-c clears the line",
+			input:    "catch\x03help\r",
+			expected: []string{"help"},
+		},
+		{
+			name:     "multiple lines",
+			input:    "help\rmap\n",
+			expected: []string{"help", "map"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			editor := New(strings.NewReader(c.input), io.Discard)
+			for _, line := range c.history {
+				editor.AddHistory(line)
+			}
+
+			for _, expected := range c.expected {
+				actual, err := editor.ReadLine()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if actual != expected {
+					t.Errorf("expected %q, got %q", expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	words := []string{"catch", "charmander", "charmeleon", "explore"}
+	complete := func(head string) []string {
+		fields := strings.Fields(head)
+		word := ""
+		if len(fields) > 0 && !strings.HasSuffix(head, " ") {
+			word = fields[len(fields)-1]
+		}
+		matches := []string{}
+		for _, w := range words {
+			if strings.HasPrefix(w, word) {
+				matches = append(matches, w)
+			}
+		}
+		return matches
+	}
+
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "ex\t\r", expected: "explore "},
+		{input: "catch charm\t\r", expected: "catch charm"},
+		{input: "catch ch\t\r", expected: "catch charm"},
+		{input: "catch ch\tel\t\r", expected: "catch charmeleon "},
+		{input: "xyz\t\r", expected: "xyz"},
+	}
+
+	for _, c := range cases {
+		editor := New(strings.NewReader(c.input), io.Discard)
+		editor.Complete = complete
+
+		actual, err := editor.ReadLine()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if actual != c.expected {
+			t.Errorf("for input %q expected %q, got %q", c.input, c.expected, actual)
+		}
+	}
+}
+
+func TestReadLineEOF(t *testing.T) {
+	editor := New(strings.NewReader("\x04"), io.Discard)
+	if _, err := editor.ReadLine(); err != io.EOF {
+		t.Errorf("expected io.EOF on ctrl-d, got %v", err)
+	}
+
+	editor = New(strings.NewReader(""), io.Discard)
+	if _, err := editor.ReadLine(); err != io.EOF {
+		t.Errorf("expected io.EOF at end of input, got %v", err)
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	if err := os.WriteFile(path, []byte("map\nhelp\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	editor := New(strings.NewReader("pokedex\r"), io.Discard)
+	if err := editor.LoadHistory(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := editor.ReadLine(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := editor.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "map\nhelp\npokedex\n" {
+		t.Errorf("unexpected history file contents %q", data)
+	}
+}
+
+func TestHistoryFileIsTrimmed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	var lines strings.Builder
+	for i := 0; i < maxHistory+10; i++ {
+		fmt.Fprintf(&lines, "seed %d\n", i)
+	}
+	if err := os.WriteFile(path, []byte(lines.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	editor := New(strings.NewReader(""), io.Discard)
+	if err := editor.LoadHistory(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	editor.AddHistory("pokedex")
+	if err := editor.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(saved) != maxHistory+1 {
+		t.Fatalf("history file has %d lines, expected %d", len(saved), maxHistory+1)
+	}
+	if saved[0] != "seed 10" || saved[len(saved)-1] != "pokedex" {
+		t.Errorf("history file runs from %q to %q", saved[0], saved[len(saved)-1])
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+)
+
+func rollHeldItem(rng *rand.Rand, pokemon RespPokemon, version string) string {
+	for _, held := range pokemon.HeldItems {
+		rarity := 0
+		for _, detail := range held.VersionDetails {
+			if version != "" && detail.Version.Name != version {
+				continue
+			}
+			if detail.Rarity > rarity {
+				rarity = detail.Rarity
+			}
+		}
+		if rarity > 0 && rng.Intn(100) < rarity {
+			return held.Item.Name
+		}
+	}
+	return ""
+}
+
+func commandGive(cfg *config, args ...string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: give <pokemon_name> <item_name>")
+	}
+
+	pokemonName, itemName := normalizeName(args[0]), normalizeName(args[1])
+
+	pokemon, exists := cfg.caughtPokemon[pokemonName]
+	if !exists {
+		return emit(cfg, messageResult{"you have not caught that pokemon"})
+	}
+	if cfg.bag[itemName] == 0 {
+		return fmt.Errorf("you don't have a %s in your bag", itemName)
+	}
+	if pokemon.HeldItem != "" {
+		return fmt.Errorf("%s is already holding %s", pokemonName, pokemon.HeldItem)
+	}
+
+	removeFromBag(cfg, itemName)
+	pokemon.HeldItem = itemName
+	cfg.caughtPokemon[pokemonName] = pokemon
+
+	return emit(cfg, messageResult{fmt.Sprintf("%s is now holding %s", pokemonName, itemName)})
+}
+
+func commandTake(cfg *config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: take <pokemon_name>")
+	}
+
+	pokemonName := normalizeName(args[0])
+
+	pokemon, exists := cfg.caughtPokemon[pokemonName]
+	if !exists {
+		return emit(cfg, messageResult{"you have not caught that pokemon"})
+	}
+	if pokemon.HeldItem == "" {
+		return emit(cfg, messageResult{fmt.Sprintf("%s isn't holding anything", pokemonName)})
+	}
+
+	itemName := pokemon.HeldItem
+	cfg.bag[itemName]++
+	pokemon.HeldItem = ""
+	cfg.caughtPokemon[pokemonName] = pokemon
+
+	return emit(cfg, messageResult{fmt.Sprintf("Took %s from %s and put it in your bag", itemName, pokemonName)})
+}
+
+type bagResult struct {
+	Items []bagItem `json:"items"`
+}
+
+type bagItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func (r bagResult) renderText(w io.Writer) {
+	fmt.Fprintln(w, "Your Bag:")
+
+	if len(r.Items) == 0 {
+		fmt.Fprintln(w, "Your bag is empty!")
+		return
+	}
+
+	for _, item := range r.Items {
+		fmt.Fprintf(w, " - %s x%d\n", item.Name, item.Count)
+	}
+}
+
+func commandBag(cfg *config, args ...string) error {
+	names := make([]string, 0, len(cfg.bag))
+	for name := range cfg.bag {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := bagResult{Items: []bagItem{}}
+	for _, name := range names {
+		result.Items = append(result.Items, bagItem{name, cfg.bag[name]})
+	}
+	return emit(cfg, result)
+}
+
+func removeFromBag(cfg *config, itemName string) {
+	cfg.bag[itemName]--
+	if cfg.bag[itemName] <= 0 {
+		delete(cfg.bag, itemName)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+var listEndpoints = []string{
+	"ability", "berry", "egg-group", "generation", "item", "location", "location-area",
+	"move", "nature", "pokedex", "pokemon", "pokemon-species", "region", "type", "version",
+}
+
+type listResult struct {
+	Resource string   `json:"resource"`
+	Results  []string `json:"results"`
+	pagePosition
+}
+
+func (r listResult) renderText(w io.Writer) {
+	for _, name := range r.Results {
+		fmt.Fprintln(w, name)
+	}
+	r.renderFooter(w, len(r.Results))
+}
+
+func commandList(cfg *config, args ...string) error {
+	const usage = "usage: list <resource> [next|prev|first|last] [--page N] [--limit N]"
+	if len(args) == 0 {
+		return fmt.Errorf(usage)
+	}
+
+	resource := normalizeName(args[0])
+	if !slices.Contains(listEndpoints, resource) {
+		return fmt.Errorf("unknown list %q, try one of %s", resource, strings.Join(listEndpoints, ", "))
+	}
+
+	flags, positional, err := parseFlags(args[1:], []string{"page", "limit"}, nil)
+	if err != nil {
+		return err
+	}
+
+	pager := pagerFor(cfg, resource)
+	offset, limit, err := pager.target(cfg, flags, positional, usage)
+	if err == errFirstPage || err == errLastPage {
+		return emit(cfg, messageResult{err.Error()})
+	}
+	if err != nil {
+		return err
+	}
+
+	listResp, position, err := pager.show(cfg, offset, limit)
+	if err != nil {
+		return err
+	}
+
+	result := listResult{Resource: resource, Results: []string{}, pagePosition: position}
+	for _, entry := range listResp.Results {
+		result.Results = append(result.Results, entry.Name)
+		if resource == "location-area" {
+			cfg.knownAreas[entry.Name] = true
+		}
+	}
+	return emit(cfg, result)
+}
+
+func completeList(cfg *config, args ...string) []string {
+	switch len(args) {
+	case 0:
+		return listEndpoints
+	case 1:
+		return []string{"next", "prev", "first", "last"}
+	default:
+		return nil
+	}
+}
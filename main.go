@@ -1,34 +1,72 @@
 package main
 
 import (
-	"bufio"
+	"cmp"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
+	"maps"
 	"math/rand"
 	"net/http"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/Professor-Goo/pokedexcli/internal/lineedit"
 	"github.com/Professor-Goo/pokedexcli/internal/pokecache"
 )
 
 type config struct {
-	pokeapiClient       pokecache.Cache
-	nextLocationURL     *string
-	previousLocationURL *string
-	caughtPokemon       map[string]RespPokemon
+	pokeapiClient  pokecache.Cache
+	pagers         map[string]*listPager
+	nameIndex      map[string][]string
+	mapRegion      string
+	caughtPokemon  map[string]ownedPokemon
+	pokedex        map[int]dexRecord
+	wildLevels     map[string]levelRange
+	shinyOdds      int
+	gameVersion    string
+	language       string
+	bag            map[string]int
+	rng            *rand.Rand
+	seed           int64
+	knownAreas     map[string]bool
+	lastExplored   []string
+	closers        []io.Closer
+	stopOnError    bool
+	outputFormat   string
+	out            io.Writer
+	errOut         io.Writer
+	aliases        map[string]string
+	runningAliases map[string]bool
+	configPath     string
+}
+
+type ownedPokemon struct {
+	RespPokemon
+	Nickname string
+	Level    int
+	Ability  string
+	Shiny    bool
+	HeldItem string
+	CaughtAt time.Time
+	History  []string
+}
+
+type levelRange struct {
+	min int
+	max int
 }
 
 type RespShallowLocations struct {
-	Count    int     `json:"count"`
-	Next     *string `json:"next"`
-	Previous *string `json:"previous"`
-	Results  []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"results"`
+	Count    int                `json:"count"`
+	Next     *string            `json:"next"`
+	Previous *string            `json:"previous"`
+	Results  []namedAPIResource `json:"results"`
 }
 
 type RespLocationArea struct {
@@ -66,9 +104,9 @@ type RespLocationArea struct {
 		} `json:"pokemon"`
 		VersionDetails []struct {
 			EncounterDetails []struct {
-				Chance          int   `json:"chance"`
-				ConditionValues []any `json:"condition_values"`
-				MaxLevel        int   `json:"max_level"`
+				Chance          int                `json:"chance"`
+				ConditionValues []namedAPIResource `json:"condition_values"`
+				MaxLevel        int                `json:"max_level"`
 				Method          struct {
 					Name string `json:"name"`
 					URL  string `json:"url"`
@@ -365,252 +403,775 @@ type RespPokemon struct {
 type cliCommand struct {
 	name        string
 	description string
+	usage       string
+	args        []commandArg
+	examples    []string
+	category    string
+	aliases     []string
 	callback    func(*config, ...string) error
+	completer   func(*config, ...string) []string
+}
+
+type commandArg struct {
+	name        string
+	description string
 }
 
 func main() {
+	seed := flag.Int64("seed", 0, "seed for catches and other random events (default: random)")
+	commandLine := flag.String("c", "", "run the given commands, separated by semicolons, and exit")
+	scriptPath := flag.String("script", "", "run the commands in the given file and exit")
+	stopOnError := flag.Bool("e", false, "stop at the first failing command in -c or --script")
+	outputFormat := flag.String("output", "text", "output format: text, json or yaml")
+	flag.Usage = printUsage
+	flag.Parse()
+
+	seedSet := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seedSet = true
+		}
+	})
+	if !seedSet {
+		*seed = time.Now().UnixNano()
+	}
+
 	pokeClient := pokecache.NewCache(5 * time.Minute)
 	cfg := &config{
 		pokeapiClient: pokeClient,
-		caughtPokemon: make(map[string]RespPokemon),
+		caughtPokemon: make(map[string]ownedPokemon),
+		pokedex:       make(map[int]dexRecord),
+		wildLevels:    make(map[string]levelRange),
+		shinyOdds:     defaultShinyOdds,
+		language:      defaultLanguage,
+		bag:           make(map[string]int),
+		knownAreas:    make(map[string]bool),
+		out:           os.Stdout,
+		errOut:        os.Stderr,
+		aliases:       make(map[string]string),
 	}
-
-	commands := getCommands()
-
-	scanner := bufio.NewScanner(os.Stdin)
-
-	for {
-		fmt.Print("Pokedex > ")
-
-		scanner.Scan()
-		input := scanner.Text()
-
-		cleanedInput := cleanInput(input)
-
-		if len(cleanedInput) == 0 {
-			continue
+	setSeed(cfg, *seed)
+	if dir, err := configDir(); err == nil {
+		cfg.configPath = filepath.Join(dir, "config.json")
+		if err := loadUserConfig(cfg); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: could not load config:", err)
 		}
+	}
+	cfg.stopOnError = *stopOnError
 
-		commandName := cleanedInput[0]
-		args := []string{}
-		if len(cleanedInput) > 1 {
-			args = cleanedInput[1:]
+	if !validOutputFormat(*outputFormat) {
+		fmt.Fprintln(os.Stderr, "Error: --output must be one of text, json or yaml")
+		os.Exit(2)
+	}
+	cfg.outputFormat = *outputFormat
+
+	if *commandLine != "" || *scriptPath != "" {
+		script := *commandLine
+		if *scriptPath != "" {
+			if *commandLine != "" {
+				fmt.Fprintln(os.Stderr, "Error: use only one of -c and --script")
+				os.Exit(2)
+			}
+			data, err := os.ReadFile(*scriptPath)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Error:", err)
+				os.Exit(1)
+			}
+			script = string(data)
 		}
 
-		command, exists := commands[commandName]
-		if !exists {
-			fmt.Println("Unknown command")
-			continue
+		if err := runScript(cfg, script); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
 		}
+		return
+	}
+
+	if flag.NArg() > 0 {
+		os.Exit(runSubcommand(cfg, flag.Args()))
+	}
 
-		err := command.callback(cfg, args...)
-		if err != nil {
-			fmt.Println("Error:", err)
+	var readLine func() (string, error)
+	if lineedit.IsTerminal(int(os.Stdin.Fd())) {
+		commands := getCommands()
+		editor := lineedit.New(os.Stdin, os.Stdout)
+		editor.Prompt = "Pokedex > "
+		editor.Complete = func(head string) []string {
+			return completeInput(cfg, commands, head)
 		}
+		if dir, err := dataDir(); err == nil {
+			if err := editor.LoadHistory(filepath.Join(dir, "history")); err != nil {
+				fmt.Fprintln(cfg.errOut, "Error: could not load history:", err)
+			}
+		}
+		cfg.closers = append(cfg.closers, editor)
+		readLine = editor.ReadLine
+	} else {
+		readLine = newScannerReader(cfg, os.Stdin)
+	}
+
+	if err := runREPL(cfg, readLine); err != nil {
+		fmt.Fprintln(os.Stderr, "Error reading input:", err)
+		os.Exit(1)
 	}
 }
 
 func getCommands() map[string]cliCommand {
-	return map[string]cliCommand{
+	commands := map[string]cliCommand{
 		"help": {
 			name:        "help",
 			description: "Displays a help message",
-			callback:    commandHelp,
+			usage:       "help [<command>]",
+			args: []commandArg{
+				{"command", "show the detailed page for this command"},
+			},
+			examples:  []string{"help", "help catch"},
+			category:  categorySession,
+			callback:  commandHelp,
+			completer: completeCommands,
 		},
 		"exit": {
 			name:        "exit",
 			description: "Exit the Pokedex",
+			usage:       "exit",
+			category:    categorySession,
 			callback:    commandExit,
 		},
 		"map": {
 			name:        "map",
-			description: "Displays the names of 20 location areas in the Pokemon world. Each subsequent call displays the next 20 locations.",
-			callback:    commandMap,
+			description: "Displays the next page of location areas in the Pokemon world, or jumps to a page",
+			usage:       "map [first|last] [--page N] [--limit N] [--region R]",
+			args: []commandArg{
+				{"first, last", "jump to the first or last page"},
+				{"--page N", "jump to page N"},
+				{"--limit N", "show N areas per page from now on (default 20)"},
+				{"--region R", "only show areas in region R from now on; any shows all areas again"},
+			},
+			examples: []string{"map", "map --page 4", "map last", "map --limit 50", "map --region sinnoh"},
+			category: categoryExploring,
+			aliases:  []string{"n"},
+			callback: commandMap,
 		},
 		"mapb": {
 			name:        "mapb",
-			description: "Displays the names of the previous 20 location areas in the Pokemon world. It's a way to go back.",
+			description: "Displays the previous page of location areas in the Pokemon world. It's a way to go back.",
+			usage:       "mapb",
+			category:    categoryExploring,
+			aliases:     []string{"b"},
 			callback:    commandMapb,
 		},
+		"list": {
+			name:        "list",
+			description: "Browse any PokeAPI list a page at a time, like map does for location areas",
+			usage:       "list <resource> [next|prev|first|last] [--page N] [--limit N]",
+			args: []commandArg{
+				{"resource", "a list such as pokemon, type, item, move, location or region"},
+				{"next, prev", "step one page forward (the default) or back"},
+				{"first, last", "jump to the first or last page"},
+				{"--page N", "jump to page N"},
+				{"--limit N", "show N entries per page from now on (default 20)"},
+			},
+			examples:  []string{"list pokemon", "list pokemon next", "list type --limit 50", "list item --page 3", "list move prev"},
+			category:  categoryExploring,
+			callback:  commandList,
+			completer: completeList,
+		},
+		"search": {
+			name:        "search",
+			description: "Search pokemon, location area, item and move names, allowing for typos",
+			usage:       "search <term> [--kind pokemon|area|item|move] [--limit N]",
+			args: []commandArg{
+				{"term", "part of a name, or a misspelled one"},
+				{"--kind", "only search pokemon, area, item or move names"},
+				{"--limit N", "show at most N results (default 10)"},
+			},
+			examples:  []string{"search char", "search charmandr --kind pokemon", "search canalave --kind area"},
+			category:  categoryExploring,
+			callback:  commandSearch,
+			completer: completeSearch,
+		},
+		"regions": {
+			name:        "regions",
+			description: "List the regions of the Pokemon world",
+			usage:       "regions",
+			category:    categoryExploring,
+			callback:    commandRegions,
+		},
+		"region": {
+			name:        "region",
+			description: "List the locations in a region",
+			usage:       "region <region_name>",
+			args: []commandArg{
+				{"region_name", "a region as shown by regions"},
+			},
+			examples:  []string{"region sinnoh"},
+			category:  categoryExploring,
+			callback:  commandRegion,
+			completer: completeRegions,
+		},
+		"location": {
+			name:        "location",
+			description: "List the areas of a location",
+			usage:       "location <location_name>",
+			args: []commandArg{
+				{"location_name", "a location as shown by region"},
+			},
+			examples: []string{"location pastoria-city"},
+			category: categoryExploring,
+			callback: commandLocation,
+		},
 		"explore": {
 			name:        "explore",
-			description: "Explore a location area",
-			callback:    commandExplore,
+			description: "Explore a location area and list its pokemon by encounter method, level and chance",
+			usage:       "explore <area_name> [--version v] [--sort rarity|name]",
+			args: []commandArg{
+				{"area_name", "a location area name as shown by map"},
+				{"--version v", "only show encounters in game version v (default: the version setting)"},
+				{"--sort", "list the rarest pokemon first, or sort by name"},
+			},
+			examples:  []string{"explore pastoria-city-area", "explore pastoria-city-area --version pearl --sort rarity"},
+			category:  categoryExploring,
+			aliases:   []string{"e"},
+			callback:  commandExplore,
+			completer: completeAreas,
+		},
+		"where": {
+			name:        "where",
+			description: "List the location areas where a pokemon can be found, by game version",
+			usage:       "where <pokemon_name> [--version v]",
+			args: []commandArg{
+				{"pokemon_name", "any pokemon, caught or not"},
+				{"--version v", "only show game version v (default: the version setting)"},
+			},
+			examples:  []string{"where pikachu", "where magikarp --version diamond"},
+			category:  categoryExploring,
+			callback:  commandWhere,
+			completer: completePokemon,
 		},
 		"catch": {
 			name:        "catch",
 			description: "Attempt to catch a pokemon",
-			callback:    commandCatch,
+			usage:       "catch <pokemon_name>",
+			args: []commandArg{
+				{"pokemon_name", "a pokemon, form or species name"},
+			},
+			examples:  []string{"catch pikachu", "catch vulpix-alola"},
+			category:  categoryPokemon,
+			aliases:   []string{"c"},
+			callback:  commandCatch,
+			completer: completePokemon,
 		},
 		"inspect": {
 			name:        "inspect",
 			description: "Display details of a caught pokemon",
-			callback:    commandInspect,
+			usage:       "inspect <pokemon_name>",
+			args: []commandArg{
+				{"pokemon_name", "a pokemon you have caught"},
+			},
+			examples:  []string{"inspect pikachu"},
+			category:  categoryPokemon,
+			aliases:   []string{"i"},
+			callback:  commandInspect,
+			completer: completeOwned,
+		},
+		"alias": {
+			name:        "alias",
+			description: "List, define or replace your own command aliases and macros",
+			usage:       "alias [<name> = <commands>]",
+			args: []commandArg{
+				{"name", "the new command name"},
+				{"commands", "commands separated by ;, where $1-$9 and $@ stand for the alias arguments"},
+			},
+			examples:  []string{"alias", `alias hunt = "explore $1; catch $2"`, "alias cp = catch pikachu"},
+			category:  categorySession,
+			callback:  commandAlias,
+			completer: completeAliases,
+		},
+		"unalias": {
+			name:        "unalias",
+			description: "Remove one of your aliases",
+			usage:       "unalias <name>",
+			args: []commandArg{
+				{"name", "an alias defined with alias"},
+			},
+			examples:  []string{"unalias hunt"},
+			category:  categorySession,
+			callback:  commandUnalias,
+			completer: completeAliases,
+		},
+		"nickname": {
+			name:        "nickname",
+			description: "Give a caught pokemon a nickname, or clear it",
+			usage:       "nickname <pokemon_name> [<nickname>]",
+			args: []commandArg{
+				{"pokemon_name", "a pokemon you have caught"},
+				{"nickname", "the new nickname; quote it to keep spaces, leave it out to clear"},
+			},
+			examples:  []string{`nickname pikachu "Sir Fluffy"`, "nickname pikachu"},
+			category:  categoryPokemon,
+			callback:  commandNickname,
+			completer: completeOwned,
 		},
 		"pokedex": {
 			name:        "pokedex",
-			description: "Show all caught pokemon",
-			callback:    commandPokedex,
+			description: "Show pokedex completion, seen and caught, and your pokemon in dex order",
+			usage:       "pokedex [--dex name] [--missing] [--sort id|name|caught|level|bst] [--type t] [--gen n] [--shiny] [--page N] [--limit N]",
+			args: []commandArg{
+				{"--dex", "count progress in a regional pokedex such as kanto instead of the national one"},
+				{"--missing", "list the species not caught yet instead of your pokemon"},
+				{"--sort", "order by dex number (the default), name, time caught, level or base stat total"},
+				{"--type", "only list your pokemon of this type"},
+				{"--gen", "only list species from this generation, 1 to 9"},
+				{"--shiny", "only list your shiny pokemon"},
+				{"--page", "which page to show, 1 by default"},
+				{"--limit", "how many pokemon to show per page, 20 by default"},
+			},
+			examples: []string{"pokedex", "pokedex --missing --gen 1", "pokedex --dex kanto --missing", "pokedex --sort level --type water"},
+			category: categoryPokemon,
+			callback: commandPokedex,
+		},
+		"compare": {
+			name:        "compare",
+			description: "Compare pokemon side by side: types, abilities, size, base stats and type matchups",
+			usage:       "compare <pokemon_name> <pokemon_name> [<pokemon_name>...]",
+			args: []commandArg{
+				{"pokemon_name", "two or more pokemon, caught or not"},
+			},
+			examples:  []string{"compare pikachu raichu", "compare magikarp gyarados pikachu"},
+			category:  categoryPokemon,
+			callback:  commandCompare,
+			completer: completeCompare,
+		},
+		"dex": {
+			name:        "dex",
+			description: "Show the pokedex entry of any pokemon: genus, flavor text, habitat, breeding and more",
+			usage:       "dex <pokemon_name>",
+			args: []commandArg{
+				{"pokemon_name", "any pokemon, caught or not"},
+			},
+			examples:  []string{"dex bulbasaur", "dex pikachu"},
+			category:  categoryPokemon,
+			callback:  commandDex,
+			completer: completePokemon,
+		},
+		"evolution": {
+			name:        "evolution",
+			description: "Show the evolution chain of a pokemon",
+			usage:       "evolution <pokemon_name>",
+			args: []commandArg{
+				{"pokemon_name", "any pokemon, caught or not"},
+			},
+			examples:  []string{"evolution eevee"},
+			category:  categoryPokemon,
+			callback:  commandEvolution,
+			completer: completePokemon,
+		},
+		"evolve": {
+			name:        "evolve",
+			description: "Evolve a caught pokemon that meets its evolution condition",
+			usage:       "evolve <pokemon_name>",
+			args: []commandArg{
+				{"pokemon_name", "a pokemon you have caught"},
+			},
+			examples:  []string{"evolve magikarp"},
+			category:  categoryPokemon,
+			callback:  commandEvolve,
+			completer: completeOwned,
+		},
+		"ability": {
+			name:        "ability",
+			description: "Describe an ability and list the pokemon that can have it",
+			usage:       "ability <ability_name>",
+			args: []commandArg{
+				{"ability_name", "an ability name as shown by inspect"},
+			},
+			examples: []string{"ability static"},
+			category: categoryPokemon,
+			callback: commandAbility,
+		},
+		"set": {
+			name:        "set",
+			description: "Show or change a setting",
+			usage:       "set [<option> <value>] | set -e | set +e",
+			args: []commandArg{
+				{"option", "shiny-odds, version, language or output"},
+				{"value", "the new value; version accepts any to clear it"},
+				{"-e, +e", "stop or keep going when a script command fails"},
+			},
+			examples:  []string{"set", "set shiny-odds 512", "set version diamond", "set language fr", "set output json"},
+			category:  categorySession,
+			callback:  commandSet,
+			completer: completeSet,
+		},
+		"give": {
+			name:        "give",
+			description: "Give an item from your bag to a caught pokemon",
+			usage:       "give <pokemon_name> <item_name>",
+			args: []commandArg{
+				{"pokemon_name", "a pokemon you have caught"},
+				{"item_name", "an item in your bag"},
+			},
+			examples:  []string{"give pikachu light-ball"},
+			category:  categoryItems,
+			callback:  commandGive,
+			completer: completeGive,
+		},
+		"take": {
+			name:        "take",
+			description: "Take the held item from a caught pokemon",
+			usage:       "take <pokemon_name>",
+			args: []commandArg{
+				{"pokemon_name", "a pokemon you have caught"},
+			},
+			examples:  []string{"take pikachu"},
+			category:  categoryItems,
+			callback:  commandTake,
+			completer: completeOwned,
 		},
+		"bag": {
+			name:        "bag",
+			description: "Show the items in your bag",
+			usage:       "bag",
+			category:    categoryItems,
+			callback:    commandBag,
+		},
+		"seed": {
+			name:        "seed",
+			description: "Show or set the random seed",
+			usage:       "seed [<number>]",
+			args: []commandArg{
+				{"number", "the new seed; the same seed replays the same catches"},
+			},
+			examples: []string{"seed", "seed 42"},
+			category: categorySession,
+			callback: commandSeed,
+		},
+	}
+
+	for _, command := range slices.Collect(maps.Values(commands)) {
+		for _, alias := range command.aliases {
+			commands[alias] = command
+		}
 	}
+	return commands
 }
 
 func commandExit(cfg *config, args ...string) error {
-	fmt.Println("Closing the Pokedex... Goodbye!")
-	os.Exit(0)
-	return nil
-}
-
-func commandHelp(cfg *config, args ...string) error {
-	fmt.Println()
-	fmt.Println("Welcome to the Pokedex!")
-	fmt.Println("Usage:")
-	fmt.Println()
-	fmt.Println("help: Displays a help message")
-	fmt.Println("exit: Exit the Pokedex")
-	fmt.Println("map: Displays the names of 20 location areas in the Pokemon world. Each subsequent call displays the next 20 locations.")
-	fmt.Println("mapb: Displays the names of the previous 20 location areas in the Pokemon world. It's a way to go back.")
-	fmt.Println("explore <area_name>: Explore a location area")
-	fmt.Println("catch <pokemon_name>: Attempt to catch a pokemon")
-	fmt.Println("inspect <pokemon_name>: Display details of a caught pokemon")
-	fmt.Println("pokedex: Show all caught pokemon")
-	fmt.Println()
-	return nil
+	return errExit
 }
 
-func commandMap(cfg *config, args ...string) error {
-	locationAreasURL := "https://pokeapi.co/api/v2/location-area"
-	if cfg.nextLocationURL != nil {
-		locationAreasURL = *cfg.nextLocationURL
+type locationAreasResult struct {
+	Region string   `json:"region,omitempty"`
+	Areas  []string `json:"areas"`
+	pagePosition
+}
+
+func (r locationAreasResult) renderText(w io.Writer) {
+	if r.Region != "" {
+		fmt.Fprintf(w, "Location areas in %s:\n", r.Region)
+	}
+	for _, area := range r.Areas {
+		fmt.Fprintln(w, area)
 	}
+	r.renderFooter(w, len(r.Areas))
+}
 
-	if val, ok := cfg.pokeapiClient.Get(locationAreasURL); ok {
-		var locationAreasResp RespShallowLocations
-		err := json.Unmarshal(val, &locationAreasResp)
-		if err != nil {
-			return err
-		}
+func newLocationAreasResult(cfg *config, resp RespShallowLocations, position pagePosition) locationAreasResult {
+	result := locationAreasResult{Areas: []string{}, pagePosition: position}
+	for _, loc := range resp.Results {
+		result.Areas = append(result.Areas, loc.Name)
+		cfg.knownAreas[loc.Name] = true
+	}
+	return result
+}
 
-		cfg.nextLocationURL = locationAreasResp.Next
-		cfg.previousLocationURL = locationAreasResp.Previous
+func commandMap(cfg *config, args ...string) error {
+	return showLocationAreas(cfg, args)
+}
 
-		for _, loc := range locationAreasResp.Results {
-			fmt.Println(loc.Name)
-		}
-		return nil
-	}
+func commandMapb(cfg *config, args ...string) error {
+	return showLocationAreas(cfg, []string{"prev"})
+}
 
-	res, err := http.Get(locationAreasURL)
+func showLocationAreas(cfg *config, args []string) error {
+	flags, positional, err := parseFlags(args, []string{"page", "limit", "region"}, nil)
 	if err != nil {
 		return err
 	}
-	defer res.Body.Close()
 
-	body, err := io.ReadAll(res.Body)
+	if region, ok := flags["region"]; ok {
+		region = normalizeName(region)
+		if region == "any" {
+			region = ""
+		}
+		if region != "" {
+			if _, err := getRegion(cfg, region); err != nil {
+				return suggestMatches(cfg, err)
+			}
+		}
+		cfg.mapRegion = region
+		// Switching regions starts from the first page unless one is given.
+		if _, hasPage := flags["page"]; !hasPage && len(positional) == 0 {
+			positional = []string{"first"}
+		}
+	}
+
+	pager := pagerFor(cfg, "location-area")
+	if cfg.mapRegion != "" {
+		pager = regionAreasPager(cfg, cfg.mapRegion)
+	}
+
+	offset, limit, err := pager.target(cfg, flags, positional, "usage: map [first|last] [--page N] [--limit N] [--region R]")
+	if err == errFirstPage || err == errLastPage {
+		return emit(cfg, messageResult{err.Error()})
+	}
 	if err != nil {
 		return err
 	}
 
-	cfg.pokeapiClient.Add(locationAreasURL, body)
-
-	var locationAreasResp RespShallowLocations
-	err = json.Unmarshal(body, &locationAreasResp)
+	locationAreasResp, position, err := pager.show(cfg, offset, limit)
 	if err != nil {
 		return err
 	}
+	result := newLocationAreasResult(cfg, locationAreasResp, position)
+	result.Region = cfg.mapRegion
+	return emit(cfg, result)
+}
 
-	cfg.nextLocationURL = locationAreasResp.Next
-	cfg.previousLocationURL = locationAreasResp.Previous
+// encounterMethodOrder lists the common encounter methods in the order
+// explore shows them. Other methods follow alphabetically.
+var encounterMethodOrder = []string{"walk", "surf", "old-rod", "good-rod", "super-rod", "rock-smash", "headbutt"}
 
-	for _, loc := range locationAreasResp.Results {
-		fmt.Println(loc.Name)
-	}
-	return nil
+type exploreResult struct {
+	Area        string            `json:"area"`
+	DisplayName string            `json:"display_name,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Pokemon     []string          `json:"pokemon"`
+	Methods     []encounterMethod `json:"methods"`
 }
 
-func commandMapb(cfg *config, args ...string) error {
-	if cfg.previousLocationURL == nil {
-		fmt.Println("you're on the first page")
-		return nil
-	}
+type encounterMethod struct {
+	Method     string         `json:"method"`
+	Encounters []encounterRow `json:"encounters"`
+}
+
+type encounterRow struct {
+	Pokemon  string `json:"pokemon"`
+	MinLevel int    `json:"min_level"`
+	MaxLevel int    `json:"max_level"`
+	Chance   int    `json:"chance"`
+	// Conditions must hold for these slots, like time-night or swarm-yes,
+	// so their chance doesn't add to the unconditioned row's.
+	Conditions []string `json:"conditions,omitempty"`
+}
 
-	if val, ok := cfg.pokeapiClient.Get(*cfg.previousLocationURL); ok {
-		var locationAreasResp RespShallowLocations
-		err := json.Unmarshal(val, &locationAreasResp)
-		if err != nil {
-			return err
+func (r exploreResult) renderText(w io.Writer) {
+	if r.DisplayName != "" {
+		fmt.Fprintf(w, "Exploring %s (%s)...\n", r.DisplayName, r.Area)
+	} else {
+		fmt.Fprintf(w, "Exploring %s...\n", r.Area)
+	}
+	if len(r.Methods) == 0 {
+		if r.Version != "" {
+			fmt.Fprintf(w, "No Pokemon found in %s\n", r.Version)
+		} else {
+			fmt.Fprintln(w, "No Pokemon found")
 		}
+		return
+	}
 
-		cfg.nextLocationURL = locationAreasResp.Next
-		cfg.previousLocationURL = locationAreasResp.Previous
+	// Pad the columns to the same width across every method's table.
+	nameWidth, levelWidth := 0, 0
+	for _, method := range r.Methods {
+		for _, row := range method.Encounters {
+			nameWidth = max(nameWidth, len(row.Pokemon))
+			levelWidth = max(levelWidth, len(levelRangeText(row.MinLevel, row.MaxLevel)))
+		}
+	}
 
-		for _, loc := range locationAreasResp.Results {
-			fmt.Println(loc.Name)
+	fmt.Fprintln(w, "Found Pokemon:")
+	for _, method := range r.Methods {
+		fmt.Fprintf(w, "%s:\n", method.Method)
+		for _, row := range method.Encounters {
+			fmt.Fprintf(w, "  %-*s  %-*s  %3d%%%s\n", nameWidth, row.Pokemon, levelWidth, levelRangeText(row.MinLevel, row.MaxLevel), row.Chance,
+				conditionsText(row.Conditions))
 		}
-		return nil
 	}
+}
 
-	res, err := http.Get(*cfg.previousLocationURL)
-	if err != nil {
-		return err
+func levelRangeText(min, max int) string {
+	if min == max {
+		return fmt.Sprintf("Lv %d", min)
 	}
-	defer res.Body.Close()
+	return fmt.Sprintf("Lv %d-%d", min, max)
+}
 
-	body, err := io.ReadAll(res.Body)
+func commandExplore(cfg *config, args ...string) error {
+	flags, positional, err := parseFlags(args, []string{"version", "sort"}, nil)
 	if err != nil {
 		return err
 	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: explore <area_name> [--version v] [--sort rarity|name]")
+	}
+
+	sortBy := flags["sort"]
+	if sortBy != "" && sortBy != "rarity" && sortBy != "name" {
+		return fmt.Errorf("--sort must be rarity or name")
+	}
+	version := cfg.gameVersion
+	if v, ok := flags["version"]; ok {
+		version = normalizeName(v)
+		if version == "any" {
+			version = ""
+		}
+	}
 
-	cfg.pokeapiClient.Add(*cfg.previousLocationURL, body)
+	areaName := normalizeName(positional[0])
 
-	var locationAreasResp RespShallowLocations
-	err = json.Unmarshal(body, &locationAreasResp)
+	locationAreaResp, err := getLocationArea(cfg, areaName)
 	if err != nil {
-		return err
+		return suggestMatches(cfg, err)
+	}
+
+	result := exploreResult{
+		Area:    areaName,
+		Version: version,
+		Pokemon: []string{},
+		Methods: encounterMethods(locationAreaResp, version, sortBy),
+	}
+	names := locationAreaResp.Names
+	if i := localized(cfg.language, len(names), func(i int) string { return names[i].Language.Name }); i >= 0 {
+		result.DisplayName = names[i].Name
+	}
+	for _, encounter := range locationAreaResp.PokemonEncounters {
+		for _, details := range encounter.VersionDetails {
+			if version == "" || details.Version.Name == version {
+				result.Pokemon = append(result.Pokemon, encounter.Pokemon.Name)
+				recordSeen(cfg, speciesNumber(cfg, namedAPIResource(encounter.Pokemon)), encounter.Pokemon.Name)
+				break
+			}
+		}
+	}
+	cfg.lastExplored = append([]string{}, result.Pokemon...)
+
+	recordWildLevels(cfg, locationAreaResp)
+	return emit(cfg, result)
+}
+
+// encounterMethods groups the encounters of an area by method. Chances of
+// several slots in one version add up; across versions the best one counts.
+// Slots that only apply under some condition get rows of their own.
+func encounterMethods(area RespLocationArea, version, sortBy string) []encounterMethod {
+	type key struct{ method, pokemon, conditions string }
+	rows := map[key]*encounterRow{}
+	order := []key{}
+
+	for _, encounter := range area.PokemonEncounters {
+		for _, details := range encounter.VersionDetails {
+			if version != "" && details.Version.Name != version {
+				continue
+			}
+			chances := map[key]int{}
+			for _, detail := range details.EncounterDetails {
+				conditions := conditionNames(detail.ConditionValues)
+				k := key{detail.Method.Name, encounter.Pokemon.Name, strings.Join(conditions, ",")}
+				row, ok := rows[k]
+				if !ok {
+					row = &encounterRow{Pokemon: k.pokemon, MinLevel: detail.MinLevel, MaxLevel: detail.MaxLevel, Conditions: conditions}
+					rows[k] = row
+					order = append(order, k)
+				}
+				row.MinLevel = min(row.MinLevel, detail.MinLevel)
+				row.MaxLevel = max(row.MaxLevel, detail.MaxLevel)
+				chances[k] += detail.Chance
+			}
+			for k, chance := range chances {
+				rows[k].Chance = max(rows[k].Chance, chance)
+			}
+		}
+	}
+
+	byMethod := map[string]*encounterMethod{}
+	methods := []*encounterMethod{}
+	for _, k := range order {
+		method, ok := byMethod[k.method]
+		if !ok {
+			method = &encounterMethod{Method: k.method}
+			byMethod[k.method] = method
+			methods = append(methods, method)
+		}
+		method.Encounters = append(method.Encounters, *rows[k])
 	}
 
-	cfg.nextLocationURL = locationAreasResp.Next
-	cfg.previousLocationURL = locationAreasResp.Previous
+	slices.SortStableFunc(methods, func(a, b *encounterMethod) int {
+		return cmp.Or(cmp.Compare(methodRank(a.Method), methodRank(b.Method)), cmp.Compare(a.Method, b.Method))
+	})
+
+	result := []encounterMethod{}
+	for _, method := range methods {
+		encounters := method.Encounters
+		switch sortBy {
+		case "rarity":
+			sort.SliceStable(encounters, func(i, j int) bool { return encounters[i].Chance < encounters[j].Chance })
+		case "name":
+			sort.SliceStable(encounters, func(i, j int) bool { return encounters[i].Pokemon < encounters[j].Pokemon })
+		}
+		result = append(result, *method)
+	}
+	return result
+}
 
-	for _, loc := range locationAreasResp.Results {
-		fmt.Println(loc.Name)
+// conditionNames lists the names of an encounter slot's conditions, in the
+// order PokeAPI gives them.
+func conditionNames(values []namedAPIResource) []string {
+	var names []string
+	for _, value := range values {
+		names = append(names, value.Name)
 	}
-	return nil
+	return names
 }
 
-func commandExplore(cfg *config, args ...string) error {
-	if len(args) != 1 {
-		return fmt.Errorf("usage: explore <area_name>")
+// conditionsText is the suffix shown after a conditional encounter row.
+func conditionsText(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return "  (" + strings.Join(conditions, ", ") + ")"
+}
+
+func methodRank(method string) int {
+	if i := slices.Index(encounterMethodOrder, method); i >= 0 {
+		return i
 	}
+	return len(encounterMethodOrder)
+}
 
-	areaName := args[0]
+func getLocationArea(cfg *config, areaName string) (RespLocationArea, error) {
 	url := "https://pokeapi.co/api/v2/location-area/" + areaName
 
-	fmt.Printf("Exploring %s...\n", areaName)
-
 	if val, ok := cfg.pokeapiClient.Get(url); ok {
 		var locationAreaResp RespLocationArea
 		err := json.Unmarshal(val, &locationAreaResp)
-		if err != nil {
-			return err
-		}
-
-		fmt.Println("Found Pokemon:")
-		for _, encounter := range locationAreaResp.PokemonEncounters {
-			fmt.Printf(" - %s\n", encounter.Pokemon.Name)
-		}
-		return nil
+		return locationAreaResp, err
 	}
 
 	res, err := http.Get(url)
 	if err != nil {
-		return err
+		return RespLocationArea{}, err
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotFound {
+		return RespLocationArea{}, &notFoundError{Kind: "area", Name: areaName}
+	}
+	if res.StatusCode > 299 {
+		return RespLocationArea{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return err
+		return RespLocationArea{}, err
 	}
 
 	cfg.pokeapiClient.Add(url, body)
@@ -618,14 +1179,54 @@ func commandExplore(cfg *config, args ...string) error {
 	var locationAreaResp RespLocationArea
 	err = json.Unmarshal(body, &locationAreaResp)
 	if err != nil {
-		return err
+		return RespLocationArea{}, err
 	}
 
-	fmt.Println("Found Pokemon:")
-	for _, encounter := range locationAreaResp.PokemonEncounters {
-		fmt.Printf(" - %s\n", encounter.Pokemon.Name)
+	return locationAreaResp, nil
+}
+
+func recordWildLevels(cfg *config, area RespLocationArea) {
+	for _, encounter := range area.PokemonEncounters {
+		pokemonName := encounter.Pokemon.Name
+		for _, version := range encounter.VersionDetails {
+			for _, detail := range version.EncounterDetails {
+				r, ok := cfg.wildLevels[pokemonName]
+				if !ok || detail.MinLevel < r.min {
+					r.min = detail.MinLevel
+				}
+				if !ok || detail.MaxLevel > r.max {
+					r.max = detail.MaxLevel
+				}
+				cfg.wildLevels[pokemonName] = r
+			}
+		}
 	}
-	return nil
+}
+
+type catchResult struct {
+	Pokemon  string `json:"pokemon"`
+	Caught   bool   `json:"caught"`
+	Level    int    `json:"level,omitempty"`
+	Ability  string `json:"ability,omitempty"`
+	Shiny    bool   `json:"shiny"`
+	HeldItem string `json:"held_item,omitempty"`
+}
+
+func (r catchResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Throwing a Pokeball at %s...\n", r.Pokemon)
+	if !r.Caught {
+		fmt.Fprintf(w, "%s escaped!\n", r.Pokemon)
+		return
+	}
+
+	fmt.Fprintf(w, "%s was caught!\n", r.Pokemon)
+	if r.Shiny {
+		fmt.Fprintln(w, "It's shiny!")
+	}
+	if r.HeldItem != "" {
+		fmt.Fprintf(w, "It was holding %s!\n", r.HeldItem)
+	}
+	fmt.Fprintln(w, "You may now inspect it with the inspect command.")
 }
 
 func commandCatch(cfg *config, args ...string) error {
@@ -633,32 +1234,117 @@ func commandCatch(cfg *config, args ...string) error {
 		return fmt.Errorf("usage: catch <pokemon_name>")
 	}
 
-	pokemonName := args[0]
-	fmt.Printf("Throwing a Pokeball at %s...\n", pokemonName)
+	pokemonName := normalizeName(args[0])
 
-	pokemon, err := getPokemon(cfg, pokemonName)
+	pokemon, err := resolvePokemon(cfg, pokemonName)
 	if err != nil {
 		return err
 	}
+	if _, exists := cfg.caughtPokemon[pokemonName]; exists {
+		return fmt.Errorf("you already have a %s", pokemonName)
+	}
 
 	catchThreshold := 50 + (pokemon.BaseExperience / 3)
 	if catchThreshold > 255 {
 		catchThreshold = 255
 	}
 
-	randNum := rand.Intn(256)
+	randNum := cfg.rng.Intn(256)
 
 	if randNum < catchThreshold {
-		fmt.Printf("%s escaped!\n", pokemonName)
-		return nil
+		return emit(cfg, catchResult{Pokemon: pokemonName})
 	}
 
-	fmt.Printf("%s was caught!\n", pokemonName)
-	fmt.Println("You may now inspect it with the inspect command.")
+	level := 5
+	if r, ok := cfg.wildLevels[pokemonName]; ok {
+		level = r.min + cfg.rng.Intn(r.max-r.min+1)
+	}
 
-	cfg.caughtPokemon[pokemonName] = pokemon
+	owned := ownedPokemon{
+		RespPokemon: pokemon,
+		Level:       level,
+		Shiny:       cfg.rng.Intn(cfg.shinyOdds) == 0,
+		HeldItem:    rollHeldItem(cfg.rng, pokemon, cfg.gameVersion),
+		Ability:     rollAbility(cfg.rng, pokemon),
+		CaughtAt:    time.Now(),
+		History:     []string{fmt.Sprintf("caught at level %d", level)},
+	}
+	cfg.caughtPokemon[pokemonName] = owned
+	recordCaught(cfg, resourceID(pokemon.Species.URL), pokemon.Species.Name)
+
+	return emit(cfg, catchResult{
+		Pokemon:  pokemonName,
+		Caught:   true,
+		Level:    owned.Level,
+		Ability:  owned.Ability,
+		Shiny:    owned.Shiny,
+		HeldItem: owned.HeldItem,
+	})
+}
 
-	return nil
+type inspectResult struct {
+	Name      string        `json:"name"`
+	Nickname  string        `json:"nickname,omitempty"`
+	Level     int           `json:"level"`
+	Ability   string        `json:"ability"`
+	HeldItem  string        `json:"held_item,omitempty"`
+	Shiny     bool          `json:"shiny"`
+	Sprite    string        `json:"sprite"`
+	Height    int           `json:"height"`
+	Weight    int           `json:"weight"`
+	Stats     []statValue   `json:"stats"`
+	Types     []string      `json:"types"`
+	Abilities []abilitySlot `json:"abilities"`
+	History   []string      `json:"history"`
+}
+
+type statValue struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+type abilitySlot struct {
+	Name   string `json:"name"`
+	Hidden bool   `json:"hidden"`
+}
+
+func (r inspectResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Name: %s\n", r.Name)
+	if r.Nickname != "" {
+		fmt.Fprintf(w, "Nickname: %s\n", r.Nickname)
+	}
+	fmt.Fprintf(w, "Level: %d\n", r.Level)
+	fmt.Fprintf(w, "Ability: %s\n", r.Ability)
+	if r.HeldItem != "" {
+		fmt.Fprintf(w, "Held item: %s\n", r.HeldItem)
+	}
+	if r.Shiny {
+		fmt.Fprintln(w, "Shiny: yes")
+	}
+	fmt.Fprintf(w, "Sprite: %s\n", r.Sprite)
+	fmt.Fprintf(w, "Height: %d\n", r.Height)
+	fmt.Fprintf(w, "Weight: %d\n", r.Weight)
+	fmt.Fprintln(w, "Stats:")
+	for _, stat := range r.Stats {
+		fmt.Fprintf(w, "  -%s: %d\n", stat.Name, stat.Value)
+	}
+	fmt.Fprintln(w, "Types:")
+	for _, typeName := range r.Types {
+		fmt.Fprintf(w, "  - %s\n", typeName)
+	}
+	fmt.Fprintln(w, "Abilities:")
+	for _, ability := range r.Abilities {
+		if ability.Hidden {
+			fmt.Fprintf(w, "  - %s (hidden)\n", ability.Name)
+			continue
+		}
+		fmt.Fprintf(w, "  - %s\n", ability.Name)
+	}
+	fmt.Fprintln(w, "History:")
+	for _, event := range r.History {
+		fmt.Fprintf(w, "  - %s\n", event)
+	}
+	fmt.Fprintln(w)
 }
 
 func commandInspect(cfg *config, args ...string) error {
@@ -666,28 +1352,42 @@ func commandInspect(cfg *config, args ...string) error {
 		return fmt.Errorf("usage: inspect <pokemon_name>")
 	}
 
-	pokemonName := args[0]
+	pokemonName := normalizeName(args[0])
 
 	pokemon, exists := cfg.caughtPokemon[pokemonName]
 	if !exists {
-		fmt.Println("you have not caught that pokemon")
-		return nil
+		return emit(cfg, messageResult{"you have not caught that pokemon"})
 	}
 
-	fmt.Printf("Name: %s\n", pokemon.Name)
-	fmt.Printf("Height: %d\n", pokemon.Height)
-	fmt.Printf("Weight: %d\n", pokemon.Weight)
-	fmt.Println("Stats:")
+	result := inspectResult{
+		Name:      pokemon.Name,
+		Nickname:  pokemon.Nickname,
+		Level:     pokemon.Level,
+		Ability:   pokemon.Ability,
+		HeldItem:  pokemon.HeldItem,
+		Shiny:     pokemon.Shiny,
+		Sprite:    pokemon.Sprites.FrontDefault,
+		Height:    pokemon.Height,
+		Weight:    pokemon.Weight,
+		Stats:     []statValue{},
+		Types:     []string{},
+		Abilities: []abilitySlot{},
+		History:   pokemon.History,
+	}
+	if pokemon.Shiny {
+		result.Sprite = pokemon.Sprites.FrontShiny
+	}
 	for _, stat := range pokemon.Stats {
-		fmt.Printf("  -%s: %d\n", stat.Stat.Name, stat.BaseStat)
+		result.Stats = append(result.Stats, statValue{stat.Stat.Name, stat.BaseStat})
 	}
-	fmt.Println("Types:")
 	for _, typeInfo := range pokemon.Types {
-		fmt.Printf("  - %s\n", typeInfo.Type.Name)
+		result.Types = append(result.Types, typeInfo.Type.Name)
+	}
+	for _, abilityInfo := range pokemon.Abilities {
+		result.Abilities = append(result.Abilities, abilitySlot{abilityInfo.Ability.Name, abilityInfo.IsHidden})
 	}
-	fmt.Println()
 
-	return nil
+	return emit(cfg, result)
 }
 
 func getPokemon(cfg *config, pokemonName string) (RespPokemon, error) {
@@ -705,6 +1405,13 @@ func getPokemon(cfg *config, pokemonName string) (RespPokemon, error) {
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusNotFound {
+		return RespPokemon{}, &notFoundError{Kind: "pokemon", Name: pokemonName}
+	}
+	if res.StatusCode > 299 {
+		return RespPokemon{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		return RespPokemon{}, err
@@ -720,28 +1427,3 @@ func getPokemon(cfg *config, pokemonName string) (RespPokemon, error) {
 
 	return pokemonResp, nil
 }
-
-func commandPokedex(cfg *config, args ...string) error {
-	fmt.Println("Your Pokedex:")
-
-	if len(cfg.caughtPokemon) == 0 {
-		fmt.Println("You haven't caught any pokemon yet!")
-		return nil
-	}
-
-	for name := range cfg.caughtPokemon {
-		fmt.Printf(" - %s\n", name)
-	}
-
-	return nil
-}
-
-func cleanInput(text string) []string {
-	cleaned := strings.ToLower(strings.TrimSpace(text))
-
-	if cleaned == "" {
-		return []string{}
-	}
-
-	return strings.Fields(cleaned)
-}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+func commandNickname(cfg *config, args ...string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: nickname <pokemon_name> [<nickname>]")
+	}
+
+	pokemonName := normalizeName(args[0])
+
+	pokemon, exists := cfg.caughtPokemon[pokemonName]
+	if !exists {
+		return emit(cfg, messageResult{"you have not caught that pokemon"})
+	}
+
+	nickname := ""
+	if len(args) == 2 {
+		nickname = strings.TrimSpace(args[1])
+	}
+	pokemon.Nickname = nickname
+	cfg.caughtPokemon[pokemonName] = pokemon
+
+	if nickname == "" {
+		return emit(cfg, messageResult{fmt.Sprintf("%s's nickname was cleared", pokemonName)})
+	}
+	return emit(cfg, messageResult{fmt.Sprintf("%s is now called %s", pokemonName, nickname)})
+}
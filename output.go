@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var outputFormats = []string{"text", "json", "yaml"}
+
+// textRenderer is implemented by every command result. The same value is
+// marshalled as-is for the json and yaml output formats.
+type textRenderer interface {
+	renderText(w io.Writer)
+}
+
+type messageResult struct {
+	Message string `json:"message"`
+}
+
+func (r messageResult) renderText(w io.Writer) {
+	fmt.Fprintln(w, r.Message)
+}
+
+type errorResult struct {
+	Error string `json:"error"`
+	Seed  int64  `json:"seed"`
+}
+
+func (r errorResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Error: %s (seed %d)\n", r.Error, r.Seed)
+}
+
+func emit(cfg *config, result textRenderer) error {
+	return writeResult(cfg.out, cfg.outputFormat, result)
+}
+
+func writeResult(w io.Writer, format string, result textRenderer) error {
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(result)
+	case "yaml":
+		data, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "---")
+		return writeYAML(w, data)
+	default:
+		result.renderText(w)
+		return nil
+	}
+}
+
+func validOutputFormat(format string) bool {
+	for _, f := range outputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// writeYAML converts a JSON document to block-style YAML, keeping the key
+// order of the JSON encoding so both formats share one schema.
+func writeYAML(w io.Writer, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	node, err := decodeYAMLNode(dec)
+	if err != nil {
+		return err
+	}
+
+	var buf strings.Builder
+	writeYAMLNode(&buf, node, 0)
+	_, err = io.WriteString(w, buf.String())
+	return err
+}
+
+type yamlNode struct {
+	kind   byte
+	scalar string
+	keys   []string
+	values []yamlNode
+}
+
+const (
+	yamlScalar byte = iota
+	yamlMap
+	yamlList
+)
+
+func decodeYAMLNode(dec *json.Decoder) (yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return yamlNode{}, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		node := yamlNode{kind: yamlList}
+		if v == '{' {
+			node.kind = yamlMap
+		}
+		for dec.More() {
+			if node.kind == yamlMap {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return yamlNode{}, err
+				}
+				node.keys = append(node.keys, keyTok.(string))
+			}
+			child, err := decodeYAMLNode(dec)
+			if err != nil {
+				return yamlNode{}, err
+			}
+			node.values = append(node.values, child)
+		}
+		if _, err := dec.Token(); err != nil {
+			return yamlNode{}, err
+		}
+		return node, nil
+	case string:
+		return yamlNode{scalar: yamlString(v)}, nil
+	case json.Number:
+		return yamlNode{scalar: v.String()}, nil
+	case bool:
+		return yamlNode{scalar: strconv.FormatBool(v)}, nil
+	default:
+		return yamlNode{scalar: "null"}, nil
+	}
+}
+
+func writeYAMLNode(buf *strings.Builder, node yamlNode, indent int) {
+	pad := strings.Repeat("  ", indent)
+
+	switch node.kind {
+	case yamlMap:
+		for i, key := range node.keys {
+			buf.WriteString(pad + yamlString(key) + ":")
+			writeYAMLChild(buf, node.values[i], indent+1)
+		}
+	case yamlList:
+		for _, value := range node.values {
+			buf.WriteString(pad + "-")
+			if value.kind == yamlMap && len(value.keys) > 0 {
+				// The first key of a map shares the line with the dash.
+				var item strings.Builder
+				writeYAMLNode(&item, value, indent+1)
+				buf.WriteString(" " + strings.TrimPrefix(item.String(), pad+"  "))
+				continue
+			}
+			writeYAMLChild(buf, value, indent+1)
+		}
+	default:
+		buf.WriteString(pad + node.scalar + "\n")
+	}
+}
+
+func writeYAMLChild(buf *strings.Builder, node yamlNode, indent int) {
+	switch {
+	case node.kind == yamlScalar:
+		buf.WriteString(" " + node.scalar + "\n")
+	case len(node.values) == 0 && node.kind == yamlMap:
+		buf.WriteString(" {}\n")
+	case len(node.values) == 0:
+		buf.WriteString(" []\n")
+	default:
+		buf.WriteString("\n")
+		writeYAMLNode(buf, node, indent)
+	}
+}
+
+func yamlString(s string) string {
+	if s == "" || strings.ContainsAny(s, ":#{}[],&*!|>'\"%@`\n\t\\") ||
+		strings.TrimSpace(s) != s || strings.HasPrefix(s, "-") || strings.HasPrefix(s, "?") {
+		return strconv.Quote(s)
+	}
+	// Anything a YAML 1.1 or 1.2 parser would read as a bool, null or number
+	// stays a string.
+	lower := strings.ToLower(s)
+	switch lower {
+	case "true", "false", "yes", "no", "y", "n", "on", "off", "null", "~", ".inf", "+.inf", ".nan":
+		return strconv.Quote(s)
+	}
+	if strings.HasPrefix(lower, "0x") || strings.HasPrefix(lower, "0o") || strings.HasPrefix(lower, "0b") {
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteResultYAML(t *testing.T) {
+	result := inspectResult{
+		Name:      "pikachu",
+		Level:     12,
+		Ability:   "static",
+		Sprite:    "https://example.com/25.png",
+		Stats:     []statValue{{"hp", 35}, {"speed", 90}},
+		Types:     []string{"electric"},
+		Abilities: []abilitySlot{},
+		History:   []string{"caught at level 12"},
+	}
+
+	var out strings.Builder
+	if err := writeResult(&out, "yaml", result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `---
+name: pikachu
+level: 12
+ability: static
+shiny: false
+sprite: "https://example.com/25.png"
+height: 0
+weight: 0
+stats:
+  - name: hp
+    value: 35
+  - name: speed
+    value: 90
+types:
+  - electric
+abilities: []
+history:
+  - caught at level 12
+`
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestWriteResultJSONKeepsAngleBrackets(t *testing.T) {
+	var out strings.Builder
+	if err := writeResult(&out, "json", messageResult{"usage: explore <area_name> & more"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "{\n  \"message\": \"usage: explore <area_name> & more\"\n}\n"
+	if out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestYAMLString(t *testing.T) {
+	cases := map[string]string{
+		"pikachu":      "pikachu",
+		"":             `""`,
+		"true":         `"true"`,
+		"25":           `"25"`,
+		"a: b":         `"a: b"`,
+		"- dash":       `"- dash"`,
+		"mr-mime":      "mr-mime",
+		" padded":      `" padded"`,
+		"two\nlines":   `"two\nlines"`,
+		"Sir Fluffy":   "Sir Fluffy",
+		"# not a note": `"# not a note"`,
+		"0x10":         `"0x10"`,
+		"0o17":         `"0o17"`,
+		"0b101":        `"0b101"`,
+		"1_000":        `"1_000"`,
+		".inf":         `".inf"`,
+		".NaN":         `".NaN"`,
+		"+.inf":        `"+.inf"`,
+		"-.inf":        `"-.inf"`,
+		"y":            `"y"`,
+		"N":            `"N"`,
+		"nanook":       "nanook",
+	}
+
+	for input, expected := range cases {
+		if actual := yamlString(input); actual != expected {
+			t.Errorf("for %q expected %s, got %s", input, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	pokeapiBaseURL   = "https://pokeapi.co/api/v2/"
+	defaultPageLimit = 20
+
+	// bulkPageLimit is the page size used when streaming a whole list, so
+	// even the largest lists take only a couple of requests.
+	bulkPageLimit = 1000
+)
+
+var (
+	errFirstPage = errors.New("you're on the first page")
+	errLastPage  = errors.New("you're on the last page")
+)
+
+type namedAPIResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// listPager remembers where a map-style browse of one list endpoint is, so
+// each call can step forward or back from the page shown last. Lists that
+// aren't a plain endpoint, like the areas of one region, set load instead.
+type listPager struct {
+	endpoint string
+	load     func(cfg *config, offset, limit int) (RespShallowLocations, error)
+	limit    int
+	offset   int
+	count    int
+	shown    bool
+}
+
+type pagePosition struct {
+	Page   int `json:"page"`
+	Pages  int `json:"pages"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+	Count  int `json:"count"`
+}
+
+func (p pagePosition) renderFooter(w io.Writer, shown int) {
+	if shown > 0 {
+		fmt.Fprintf(w, "page %d of %d (%d-%d of %d)\n", p.Page, p.Pages, p.Offset+1, p.Offset+shown, p.Count)
+	}
+}
+
+func pagerFor(cfg *config, endpoint string) *listPager {
+	if cfg.pagers == nil {
+		cfg.pagers = make(map[string]*listPager)
+	}
+	pager, ok := cfg.pagers[endpoint]
+	if !ok {
+		pager = &listPager{endpoint: endpoint, limit: defaultPageLimit}
+		cfg.pagers[endpoint] = pager
+	}
+	return pager
+}
+
+// target works out which page the parsed arguments ask for: next (the
+// default), prev, first, last, --page N, and its size, which --limit N
+// changes. It returns errFirstPage or errLastPage when there is nowhere to
+// move. Nothing changes until show succeeds.
+func (p *listPager) target(cfg *config, flags map[string]string, positional []string, usage string) (int, int, error) {
+	_, hasPage := flags["page"]
+	_, hasLimit := flags["limit"]
+
+	move := "next"
+	if len(positional) == 1 && !hasPage {
+		move = positional[0]
+	}
+	if len(positional) > 1 || (len(positional) == 1 && hasPage) {
+		return 0, 0, errors.New(usage)
+	}
+
+	limit, err := intFlag(flags, "limit", p.limit)
+	if err != nil {
+		return 0, 0, err
+	}
+	page, err := intFlag(flags, "page", 1)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch {
+	case hasPage:
+		offset := (page - 1) * limit
+		if p.count > 0 && offset >= p.count {
+			return 0, 0, fmt.Errorf("there are only %d pages", pageCount(p.count, limit))
+		}
+		return offset, limit, nil
+	case move == "first":
+		return 0, limit, nil
+	case move == "last":
+		if p.count == 0 {
+			resp, err := p.fetch(cfg, 0, limit)
+			if err != nil {
+				return 0, 0, err
+			}
+			p.count = resp.Count
+		}
+		return (pageCount(p.count, limit) - 1) * limit, limit, nil
+	case move == "prev":
+		if !p.shown || p.offset == 0 {
+			return 0, 0, errFirstPage
+		}
+		return max(p.offset-limit, 0), limit, nil
+	case move != "next":
+		return 0, 0, errors.New(usage)
+	case hasLimit:
+		// Stay on the page that holds the first entry currently shown.
+		if p.shown {
+			return p.offset / limit * limit, limit, nil
+		}
+		return 0, limit, nil
+	case p.shown:
+		if p.offset+limit >= p.count {
+			return 0, 0, errLastPage
+		}
+		return p.offset + limit, limit, nil
+	default:
+		return 0, limit, nil
+	}
+}
+
+// show fetches the page of limit entries at offset and makes it the current
+// page, with limit the page size from then on.
+func (p *listPager) show(cfg *config, offset, limit int) (RespShallowLocations, pagePosition, error) {
+	resp, err := p.fetch(cfg, offset, limit)
+	if err != nil {
+		return RespShallowLocations{}, pagePosition{}, err
+	}
+	if len(resp.Results) == 0 && offset > 0 {
+		return RespShallowLocations{}, pagePosition{}, fmt.Errorf("there are only %d pages", pageCount(resp.Count, limit))
+	}
+
+	p.shown = true
+	p.limit = limit
+	p.offset = offset
+	p.count = resp.Count
+
+	return resp, pagePosition{
+		Page:   offset/limit + 1,
+		Pages:  pageCount(resp.Count, limit),
+		Offset: offset,
+		Limit:  limit,
+		Count:  resp.Count,
+	}, nil
+}
+
+func (p *listPager) fetch(cfg *config, offset, limit int) (RespShallowLocations, error) {
+	if p.load != nil {
+		return p.load(cfg, offset, limit)
+	}
+	return getResourceList(cfg, p.endpoint, offset, limit)
+}
+
+// pageOf returns one page of items that are all at hand, like the ones in the
+// pokedex, with its position.
+func pageOf[T any](items []T, page, limit int) ([]T, pagePosition, error) {
+	offset := (page - 1) * limit
+	if offset > 0 && offset >= len(items) {
+		return nil, pagePosition{}, fmt.Errorf("there are only %d pages", pageCount(len(items), limit))
+	}
+	return items[offset:min(offset+limit, len(items))], pagePosition{
+		Page:   page,
+		Pages:  pageCount(len(items), limit),
+		Offset: offset,
+		Limit:  limit,
+		Count:  len(items),
+	}, nil
+}
+
+func pageCount(count, limit int) int {
+	return max((count+limit-1)/limit, 1)
+}
+
+// listPages fetches the list at endpoint one page at a time through the
+// cache, starting at offset, until the last page or until the caller stops.
+func listPages(cfg *config, endpoint string, offset, limit int) iter.Seq2[RespShallowLocations, error] {
+	return func(yield func(RespShallowLocations, error) bool) {
+		for {
+			resp, err := getResourceList(cfg, endpoint, offset, limit)
+			if !yield(resp, err) || err != nil {
+				return
+			}
+			offset += limit
+			if offset >= resp.Count || len(resp.Results) == 0 {
+				return
+			}
+		}
+	}
+}
+
+// listAll streams every resource in the list at endpoint.
+func listAll(cfg *config, endpoint string) iter.Seq2[namedAPIResource, error] {
+	return func(yield func(namedAPIResource, error) bool) {
+		for page, err := range listPages(cfg, endpoint, 0, bulkPageLimit) {
+			if err != nil {
+				yield(namedAPIResource{}, err)
+				return
+			}
+			for _, resource := range page.Results {
+				if !yield(resource, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func listURL(endpoint string, offset, limit int) string {
+	return fmt.Sprintf("%s%s?offset=%d&limit=%d", pokeapiBaseURL, endpoint, offset, limit)
+}
+
+func getResourceList(cfg *config, endpoint string, offset, limit int) (RespShallowLocations, error) {
+	url := listURL(endpoint, offset, limit)
+
+	if val, ok := cfg.pokeapiClient.Get(url); ok {
+		var listResp RespShallowLocations
+		err := json.Unmarshal(val, &listResp)
+		return listResp, err
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return RespShallowLocations{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		return RespShallowLocations{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return RespShallowLocations{}, err
+	}
+
+	cfg.pokeapiClient.Add(url, body)
+
+	var listResp RespShallowLocations
+	err = json.Unmarshal(body, &listResp)
+	if err != nil {
+		return RespShallowLocations{}, err
+	}
+
+	return listResp, nil
+}
+
+// resourceID returns the numeric ID at the end of a PokeAPI resource URL such
+// as https://pokeapi.co/api/v2/version/12/, or 0 if there is none.
+func resourceID(url string) int {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	id, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Professor-Goo/pokedexcli/internal/pokecache"
+)
+
+func TestListPages(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute)
+	for offset := 0; offset < 5; offset += 2 {
+		body := fmt.Sprintf(`{"count": 5, "results": [{"name": "type-%d"}`, offset)
+		if offset+1 < 5 {
+			body += fmt.Sprintf(`, {"name": "type-%d"}`, offset+1)
+		}
+		cache.Add(listURL("type", offset, 2), []byte(body+"]}"))
+	}
+	cfg := &config{pokeapiClient: cache}
+
+	names := []string{}
+	for page, err := range listPages(cfg, "type", 0, 2) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, resource := range page.Results {
+			names = append(names, resource.Name)
+		}
+	}
+	expected := []string{"type-0", "type-1", "type-2", "type-3", "type-4"}
+	if !slices.Equal(names, expected) {
+		t.Errorf("listPages gave %q, expected %q", names, expected)
+	}
+
+	// Stopping early must not fetch further pages. Only the first page is
+	// cached, so a request for the next one would have to go out over HTTP.
+	requests := 0
+	transport := http.DefaultTransport
+	http.DefaultTransport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return nil, errors.New("no network in tests")
+	})
+	t.Cleanup(func() { http.DefaultTransport = transport })
+
+	cache = pokecache.NewCache(time.Minute)
+	cache.Add(listURL("type", 2, 2), []byte(`{"count": 5, "results": [{"name": "type-2"}, {"name": "type-3"}]}`))
+	cfg = &config{pokeapiClient: cache}
+	pages := 0
+	for _, err := range listPages(cfg, "type", 2, 2) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		break
+	}
+	if pages != 1 || requests != 0 {
+		t.Errorf("expected to stop after one page, got %d pages and %d requests", pages, requests)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestListAllReportsErrors(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute)
+	cache.Add(listURL("item", 0, bulkPageLimit), []byte(`not json`))
+	cfg := &config{pokeapiClient: cache}
+
+	for _, err := range listAll(cfg, "item") {
+		if err == nil {
+			t.Fatal("expected an error for a malformed page")
+		}
+		return
+	}
+	t.Fatal("expected listAll to yield the error")
+}
+
+func TestPageOf(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	page, position, err := pageOf(items, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(page, []int{3, 4}) {
+		t.Errorf("page 2 = %v, expected [3 4]", page)
+	}
+	if position.Page != 2 || position.Pages != 3 || position.Offset != 2 || position.Count != 5 {
+		t.Errorf("unexpected position %+v", position)
+	}
+
+	if page, _, _ := pageOf(items, 3, 2); !slices.Equal(page, []int{5}) {
+		t.Errorf("page 3 = %v, expected [5]", page)
+	}
+	if _, _, err := pageOf(items, 4, 2); err == nil {
+		t.Error("expected an error past the last page")
+	}
+	if page, _, err := pageOf([]int{}, 1, 2); err != nil || len(page) != 0 {
+		t.Errorf("first page of nothing = %v, %v", page, err)
+	}
+}
+
+func TestPagerKeepsLimitOnFailure(t *testing.T) {
+	pager := &listPager{
+		endpoint: "location-area",
+		limit:    20,
+		load: func(cfg *config, offset, limit int) (RespShallowLocations, error) {
+			resp := RespShallowLocations{Count: 5}
+			for i := offset; i < min(offset+limit, 5); i++ {
+				resp.Results = append(resp.Results, namedAPIResource{Name: fmt.Sprintf("area-%d", i)})
+			}
+			return resp, nil
+		},
+	}
+	cfg := &config{}
+	usage := "usage: map [next|prev|first|last] [--page N] [--limit N]"
+
+	if _, _, err := pager.target(cfg, map[string]string{"limit": "50"}, []string{"middle"}, usage); err == nil {
+		t.Error("expected a usage error for an unknown move")
+	}
+	offset, limit, err := pager.target(cfg, map[string]string{"limit": "2", "page": "99"}, nil, usage)
+	if err == nil {
+		_, _, err = pager.show(cfg, offset, limit)
+	}
+	if err == nil {
+		t.Error("expected an error past the last page")
+	}
+	if pager.limit != 20 {
+		t.Errorf("failed calls changed the page size to %d", pager.limit)
+	}
+
+	offset, limit, err = pager.target(cfg, map[string]string{"limit": "2"}, nil, usage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := pager.show(cfg, offset, limit); err != nil {
+		t.Fatal(err)
+	}
+	if pager.limit != 2 {
+		t.Errorf("page size is %d after showing a page of 2", pager.limit)
+	}
+}
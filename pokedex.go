@@ -0,0 +1,412 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// nationalDexSize is the number of species in the national pokedex.
+const nationalDexSize = 1025
+
+// generations are the national dex numbers each generation introduced.
+var generations = []struct {
+	name        string
+	first, last int
+}{
+	{"generation-i", 1, 151},
+	{"generation-ii", 152, 251},
+	{"generation-iii", 252, 386},
+	{"generation-iv", 387, 493},
+	{"generation-v", 494, 649},
+	{"generation-vi", 650, 721},
+	{"generation-vii", 722, 809},
+	{"generation-viii", 810, 905},
+	{"generation-ix", 906, 1025},
+}
+
+// dexRecord is what the pokedex knows about a species. A species that has a
+// record has been seen; catching it sets Caught for good, even if the pokemon
+// is released or evolves later.
+type dexRecord struct {
+	Name   string
+	Caught bool
+}
+
+type RespPokedex struct {
+	ID    int    `json:"id"`
+	Name  string `json:"name"`
+	Names []struct {
+		Name     string           `json:"name"`
+		Language namedAPIResource `json:"language"`
+	} `json:"names"`
+	PokemonEntries []struct {
+		EntryNumber    int              `json:"entry_number"`
+		PokemonSpecies namedAPIResource `json:"pokemon_species"`
+	} `json:"pokemon_entries"`
+	Region *namedAPIResource `json:"region"`
+}
+
+type pokedexResult struct {
+	Pokedex     string            `json:"pokedex"`
+	DisplayName string            `json:"display_name,omitempty"`
+	Progress    pokedexProgress   `json:"progress"`
+	Generations []pokedexProgress `json:"generations,omitempty"`
+	Pokemon     []pokedexEntry    `json:"pokemon"`
+	Missing     []missingEntry    `json:"missing,omitempty"`
+	pagePosition
+	showMissing bool
+	filtered    bool
+}
+
+type pokedexProgress struct {
+	Name   string `json:"name"`
+	Seen   int    `json:"seen"`
+	Caught int    `json:"caught"`
+	Total  int    `json:"total"`
+}
+
+type pokedexEntry struct {
+	Number        int       `json:"number"`
+	Name          string    `json:"name"`
+	Level         int       `json:"level"`
+	BaseStatTotal int       `json:"base_stat_total"`
+	Shiny         bool      `json:"shiny"`
+	CaughtAt      time.Time `json:"caught_at"`
+}
+
+type missingEntry struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	Seen   bool   `json:"seen"`
+}
+
+func (r pokedexResult) renderText(w io.Writer) {
+	title := r.DisplayName
+	if title == "" {
+		title = r.Pokedex
+	}
+	fmt.Fprintf(w, "Pokedex (%s): %d/%d caught, %d seen\n", title, r.Progress.Caught, r.Progress.Total, r.Progress.Seen)
+
+	nameWidth := 0
+	for _, gen := range r.Generations {
+		nameWidth = max(nameWidth, len(gen.Name))
+	}
+	for _, gen := range r.Generations {
+		fmt.Fprintf(w, "  %-*s  %d/%d caught, %d seen\n", nameWidth, gen.Name, gen.Caught, gen.Total, gen.Seen)
+	}
+
+	if r.showMissing {
+		if r.Count == 0 {
+			fmt.Fprintln(w, "Nothing left to catch!")
+			return
+		}
+		fmt.Fprintf(w, "Missing (%d):\n", r.Count)
+		for _, entry := range r.Missing {
+			if entry.Seen {
+				fmt.Fprintf(w, " #%03d %s (seen)\n", entry.Number, entry.Name)
+				continue
+			}
+			fmt.Fprintf(w, " #%03d %s\n", entry.Number, entry.Name)
+		}
+		r.renderFooter(w, len(r.Missing))
+		return
+	}
+
+	fmt.Fprintln(w, "Your Pokedex:")
+	if r.Count == 0 {
+		if r.filtered {
+			fmt.Fprintln(w, "None of your pokemon match.")
+		} else {
+			fmt.Fprintln(w, "You haven't caught any pokemon yet!")
+		}
+		return
+	}
+
+	nameWidth = 0
+	for _, entry := range r.Pokemon {
+		nameWidth = max(nameWidth, len(entry.Name))
+	}
+	for _, entry := range r.Pokemon {
+		line := fmt.Sprintf(" #%03d %-*s  Lv %-3d  BST %d", entry.Number, nameWidth, entry.Name, entry.Level, entry.BaseStatTotal)
+		if entry.Shiny {
+			line += "  (shiny)"
+		}
+		fmt.Fprintln(w, line)
+	}
+	r.renderFooter(w, len(r.Pokemon))
+}
+
+func commandPokedex(cfg *config, args ...string) error {
+	const usage = "usage: pokedex [--dex name] [--missing] [--sort id|name|caught|level|bst] [--type t] [--gen n] [--shiny] [--page N] [--limit N]"
+	flags, positional, err := parseFlags(args, []string{"dex", "sort", "type", "gen", "page", "limit"}, []string{"missing", "shiny"})
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf(usage)
+	}
+	_, showMissing := flags["missing"]
+	_, onlyShiny := flags["shiny"]
+	typeName, byType := flags["type"]
+	typeName = normalizeName(typeName)
+
+	sortBy := cmp.Or(flags["sort"], "id")
+	if !slices.Contains([]string{"id", "name", "caught", "level", "bst"}, sortBy) {
+		return fmt.Errorf("--sort must be id, name, caught, level or bst")
+	}
+	if showMissing && (sortBy != "id" && sortBy != "name" || byType || onlyShiny) {
+		return fmt.Errorf("--missing can only be sorted by id or name and doesn't take --type or --shiny")
+	}
+	first, last := 1, nationalDexSize
+	if value, ok := flags["gen"]; ok {
+		if first, last, err = generationRange(value); err != nil {
+			return err
+		}
+	}
+	limit, err := intFlag(flags, "limit", defaultPageLimit)
+	if err != nil {
+		return err
+	}
+	page, err := intFlag(flags, "page", 1)
+	if err != nil {
+		return err
+	}
+
+	// numbers maps the national dex numbers in scope to their number in the
+	// selected pokedex; species lists them in that order with their names.
+	numbers := map[int]int{}
+	species := []namedAPIResource{}
+	result := pokedexResult{
+		Pokedex:     "national",
+		Pokemon:     []pokedexEntry{},
+		showMissing: showMissing,
+		filtered:    byType || onlyShiny || first != 1 || last != nationalDexSize,
+	}
+
+	if name, ok := flags["dex"]; ok && normalizeName(name) != "national" {
+		pokedex, err := getPokedex(cfg, normalizeName(name))
+		if err != nil {
+			return suggestMatches(cfg, err)
+		}
+		result.Pokedex = pokedex.Name
+		names := pokedex.Names
+		if i := localized(cfg.language, len(names), func(i int) string { return names[i].Language.Name }); i >= 0 {
+			result.DisplayName = names[i].Name
+		}
+		for _, entry := range pokedex.PokemonEntries {
+			numbers[resourceID(entry.PokemonSpecies.URL)] = entry.EntryNumber
+			species = append(species, entry.PokemonSpecies)
+		}
+	} else {
+		for i := 1; i <= nationalDexSize; i++ {
+			numbers[i] = i
+		}
+		for _, gen := range generations {
+			progress := pokedexProgress{Name: gen.name, Total: gen.last - gen.first + 1}
+			for number, record := range cfg.pokedex {
+				if number >= gen.first && number <= gen.last {
+					progress.Seen++
+					if record.Caught {
+						progress.Caught++
+					}
+				}
+			}
+			result.Generations = append(result.Generations, progress)
+		}
+	}
+
+	result.Progress = pokedexProgress{Name: result.Pokedex, Total: len(numbers)}
+	for number, record := range cfg.pokedex {
+		if _, ok := numbers[number]; ok {
+			result.Progress.Seen++
+			if record.Caught {
+				result.Progress.Caught++
+			}
+		}
+	}
+
+	if showMissing {
+		if result.Pokedex == "national" {
+			for resource, err := range listAll(cfg, "pokemon-species") {
+				if err != nil {
+					return err
+				}
+				species = append(species, resource)
+			}
+		}
+		missing := []missingEntry{}
+		for _, resource := range species {
+			national := resourceID(resource.URL)
+			number, ok := numbers[national]
+			if !ok || national < first || national > last || cfg.pokedex[national].Caught {
+				continue
+			}
+			_, seen := cfg.pokedex[national]
+			missing = append(missing, missingEntry{Number: number, Name: resource.Name, Seen: seen})
+		}
+		slices.SortFunc(missing, func(a, b missingEntry) int {
+			if sortBy == "name" {
+				return cmp.Compare(a.Name, b.Name)
+			}
+			return cmp.Compare(a.Number, b.Number)
+		})
+		result.Missing, result.pagePosition, err = pageOf(missing, page, limit)
+		if err != nil {
+			return err
+		}
+		return emit(cfg, result)
+	}
+
+	owned := []pokedexEntry{}
+	for name, pokemon := range cfg.caughtPokemon {
+		national := resourceID(pokemon.Species.URL)
+		number, ok := numbers[national]
+		if !ok || national < first || national > last {
+			continue
+		}
+		if onlyShiny && !pokemon.Shiny {
+			continue
+		}
+		if byType && !hasType(pokemon.RespPokemon, typeName) {
+			continue
+		}
+		owned = append(owned, pokedexEntry{
+			Number:        number,
+			Name:          name,
+			Level:         pokemon.Level,
+			BaseStatTotal: baseStatTotal(pokemon.RespPokemon),
+			Shiny:         pokemon.Shiny,
+			CaughtAt:      pokemon.CaughtAt,
+		})
+	}
+	slices.SortFunc(owned, func(a, b pokedexEntry) int {
+		byID := cmp.Or(cmp.Compare(a.Number, b.Number), cmp.Compare(a.Name, b.Name))
+		switch sortBy {
+		case "name":
+			return cmp.Compare(a.Name, b.Name)
+		case "caught":
+			return cmp.Or(a.CaughtAt.Compare(b.CaughtAt), byID)
+		case "level":
+			return cmp.Or(cmp.Compare(b.Level, a.Level), byID)
+		case "bst":
+			return cmp.Or(cmp.Compare(b.BaseStatTotal, a.BaseStatTotal), byID)
+		default:
+			return byID
+		}
+	})
+	result.Pokemon, result.pagePosition, err = pageOf(owned, page, limit)
+	if err != nil {
+		return err
+	}
+
+	return emit(cfg, result)
+}
+
+// generationRange returns the national dex numbers of a generation given as a
+// number, like 4, or by name, like generation-iv.
+func generationRange(value string) (int, int, error) {
+	value = normalizeName(value)
+	if n, err := strconv.Atoi(value); err == nil && n >= 1 && n <= len(generations) {
+		return generations[n-1].first, generations[n-1].last, nil
+	}
+	for _, gen := range generations {
+		if value == gen.name || "generation-"+value == gen.name {
+			return gen.first, gen.last, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("--gen must be a generation from 1 to %d", len(generations))
+}
+
+func hasType(pokemon RespPokemon, typeName string) bool {
+	for _, typeInfo := range pokemon.Types {
+		if typeInfo.Type.Name == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// baseStatTotal adds up the base stats of a pokemon.
+func baseStatTotal(pokemon RespPokemon) int {
+	total := 0
+	for _, stat := range pokemon.Stats {
+		total += stat.BaseStat
+	}
+	return total
+}
+
+// recordSeen adds a species to the pokedex unless it is already there.
+func recordSeen(cfg *config, number int, name string) {
+	if number <= 0 {
+		return
+	}
+	if _, ok := cfg.pokedex[number]; !ok {
+		cfg.pokedex[number] = dexRecord{Name: name}
+	}
+}
+
+// recordCaught marks a species as caught, which also counts as seen.
+func recordCaught(cfg *config, number int, name string) {
+	if number <= 0 {
+		return
+	}
+	cfg.pokedex[number] = dexRecord{Name: name, Caught: true}
+}
+
+// speciesNumber returns the national dex number of a pokemon. Default forms
+// share their species' ID; other forms are looked up. It returns 0 if the
+// species can't be found.
+func speciesNumber(cfg *config, pokemon namedAPIResource) int {
+	if id := resourceID(pokemon.URL); id > 0 && id <= nationalDexSize {
+		return id
+	}
+	resp, err := getPokemon(cfg, pokemon.Name)
+	if err != nil {
+		return 0
+	}
+	return resourceID(resp.Species.URL)
+}
+
+func getPokedex(cfg *config, pokedexName string) (RespPokedex, error) {
+	url := "https://pokeapi.co/api/v2/pokedex/" + pokedexName
+
+	if val, ok := cfg.pokeapiClient.Get(url); ok {
+		var pokedexResp RespPokedex
+		err := json.Unmarshal(val, &pokedexResp)
+		return pokedexResp, err
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return RespPokedex{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return RespPokedex{}, &notFoundError{Kind: "pokedex", Name: pokedexName}
+	}
+	if res.StatusCode > 299 {
+		return RespPokedex{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return RespPokedex{}, err
+	}
+
+	cfg.pokeapiClient.Add(url, body)
+
+	var pokedexResp RespPokedex
+	err = json.Unmarshal(body, &pokedexResp)
+	if err != nil {
+		return RespPokedex{}, err
+	}
+
+	return pokedexResp, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestRecordSeenKeepsCaught(t *testing.T) {
+	cfg := &config{pokedex: make(map[int]dexRecord)}
+
+	recordSeen(cfg, 25, "pikachu")
+	if record := cfg.pokedex[25]; record.Caught {
+		t.Errorf("seen pikachu is marked as caught")
+	}
+
+	recordCaught(cfg, 25, "pikachu")
+	recordSeen(cfg, 25, "pikachu")
+	if record := cfg.pokedex[25]; !record.Caught {
+		t.Errorf("seeing pikachu again cleared caught")
+	}
+
+	recordSeen(cfg, 0, "missingno")
+	if len(cfg.pokedex) != 1 {
+		t.Errorf("pokedex has %d entries, expected 1", len(cfg.pokedex))
+	}
+}
+
+func TestGenerationRange(t *testing.T) {
+	cases := map[string][2]int{
+		"1":             {1, 151},
+		"iv":            {387, 493},
+		"generation-ix": {906, 1025},
+		"Generation IV": {387, 493},
+	}
+	for value, expected := range cases {
+		first, last, err := generationRange(value)
+		if err != nil {
+			t.Errorf("generationRange(%q) failed: %v", value, err)
+			continue
+		}
+		if first != expected[0] || last != expected[1] {
+			t.Errorf("generationRange(%q) = %d-%d, expected %d-%d", value, first, last, expected[0], expected[1])
+		}
+	}
+
+	for _, value := range []string{"0", "10", "kanto"} {
+		if _, _, err := generationRange(value); err == nil {
+			t.Errorf("generationRange(%q) succeeded, expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+type RespRegion struct {
+	ID             int                `json:"id"`
+	Name           string             `json:"name"`
+	Locations      []namedAPIResource `json:"locations"`
+	MainGeneration *namedAPIResource  `json:"main_generation"`
+	Pokedexes      []namedAPIResource `json:"pokedexes"`
+	VersionGroups  []namedAPIResource `json:"version_groups"`
+}
+
+type RespLocation struct {
+	ID     int                `json:"id"`
+	Name   string             `json:"name"`
+	Region *namedAPIResource  `json:"region"`
+	Areas  []namedAPIResource `json:"areas"`
+	Names  []struct {
+		Name     string           `json:"name"`
+		Language namedAPIResource `json:"language"`
+	} `json:"names"`
+}
+
+type regionsResult struct {
+	Regions []string `json:"regions"`
+}
+
+func (r regionsResult) renderText(w io.Writer) {
+	fmt.Fprintln(w, "Regions:")
+	for _, region := range r.Regions {
+		fmt.Fprintf(w, " - %s\n", region)
+	}
+}
+
+func commandRegions(cfg *config, args ...string) error {
+	result := regionsResult{Regions: []string{}}
+	for region, err := range listAll(cfg, "region") {
+		if err != nil {
+			return err
+		}
+		result.Regions = append(result.Regions, region.Name)
+	}
+	return emit(cfg, result)
+}
+
+type regionResult struct {
+	Region     string   `json:"region"`
+	Generation string   `json:"generation,omitempty"`
+	Locations  []string `json:"locations"`
+}
+
+func (r regionResult) renderText(w io.Writer) {
+	if r.Generation != "" {
+		fmt.Fprintf(w, "Locations in %s (%s):\n", r.Region, r.Generation)
+	} else {
+		fmt.Fprintf(w, "Locations in %s:\n", r.Region)
+	}
+	for _, location := range r.Locations {
+		fmt.Fprintf(w, " - %s\n", location)
+	}
+}
+
+func commandRegion(cfg *config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: region <region_name>")
+	}
+
+	region, err := getRegion(cfg, normalizeName(args[0]))
+	if err != nil {
+		return suggestMatches(cfg, err)
+	}
+
+	result := regionResult{Region: region.Name, Locations: []string{}}
+	if region.MainGeneration != nil {
+		result.Generation = region.MainGeneration.Name
+	}
+	for _, location := range region.Locations {
+		result.Locations = append(result.Locations, location.Name)
+	}
+	return emit(cfg, result)
+}
+
+type locationResult struct {
+	Location    string   `json:"location"`
+	DisplayName string   `json:"display_name,omitempty"`
+	Region      string   `json:"region,omitempty"`
+	Areas       []string `json:"areas"`
+}
+
+func (r locationResult) renderText(w io.Writer) {
+	name := r.Location
+	if r.DisplayName != "" {
+		name = fmt.Sprintf("%s (%s)", r.DisplayName, r.Location)
+	}
+	if r.Region != "" {
+		name += " in " + r.Region
+	}
+	fmt.Fprintf(w, "%s\n", name)
+	if len(r.Areas) == 0 {
+		fmt.Fprintln(w, "No areas to explore")
+		return
+	}
+	fmt.Fprintln(w, "Areas:")
+	for _, area := range r.Areas {
+		fmt.Fprintf(w, " - %s\n", area)
+	}
+}
+
+func commandLocation(cfg *config, args ...string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: location <location_name>")
+	}
+
+	location, err := getLocation(cfg, normalizeName(args[0]))
+	if err != nil {
+		return suggestMatches(cfg, err)
+	}
+
+	result := locationResult{Location: location.Name, Areas: []string{}}
+	names := location.Names
+	if i := localized(cfg.language, len(names), func(i int) string { return names[i].Language.Name }); i >= 0 {
+		result.DisplayName = names[i].Name
+	}
+	if location.Region != nil {
+		result.Region = location.Region.Name
+	}
+	for _, area := range location.Areas {
+		result.Areas = append(result.Areas, area.Name)
+		cfg.knownAreas[area.Name] = true
+	}
+	return emit(cfg, result)
+}
+
+// regionAreasPager pages through the location areas of one region, which has
+// no list endpoint of its own, by following region to locations to areas.
+// That takes a request per location, so the list is built once and kept on
+// the pager for the rest of the session.
+func regionAreasPager(cfg *config, region string) *listPager {
+	pager := pagerFor(cfg, "location-area?region="+region)
+	if pager.load != nil {
+		return pager
+	}
+
+	var areas []namedAPIResource
+	pager.load = func(cfg *config, offset, limit int) (RespShallowLocations, error) {
+		if areas == nil {
+			loaded, err := getRegionAreas(cfg, region)
+			if err != nil {
+				return RespShallowLocations{}, err
+			}
+			areas = loaded
+		}
+		start := min(offset, len(areas))
+		end := min(offset+limit, len(areas))
+		return RespShallowLocations{Count: len(areas), Results: areas[start:end]}, nil
+	}
+	return pager
+}
+
+func getRegionAreas(cfg *config, regionName string) ([]namedAPIResource, error) {
+	region, err := getRegion(cfg, regionName)
+	if err != nil {
+		return nil, err
+	}
+
+	areas := []namedAPIResource{}
+	for _, loc := range region.Locations {
+		location, err := getLocation(cfg, loc.Name)
+		if err != nil {
+			return nil, err
+		}
+		areas = append(areas, location.Areas...)
+	}
+	return areas, nil
+}
+
+func completeRegions(cfg *config, args ...string) []string {
+	if len(args) > 0 {
+		return nil
+	}
+	names := []string{}
+	for region, err := range listAll(cfg, "region") {
+		if err != nil {
+			return nil
+		}
+		names = append(names, region.Name)
+	}
+	return names
+}
+
+func getRegion(cfg *config, regionName string) (RespRegion, error) {
+	url := "https://pokeapi.co/api/v2/region/" + regionName
+
+	if val, ok := cfg.pokeapiClient.Get(url); ok {
+		var regionResp RespRegion
+		err := json.Unmarshal(val, &regionResp)
+		return regionResp, err
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return RespRegion{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return RespRegion{}, &notFoundError{Kind: "region", Name: regionName}
+	}
+	if res.StatusCode > 299 {
+		return RespRegion{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return RespRegion{}, err
+	}
+
+	cfg.pokeapiClient.Add(url, body)
+
+	var regionResp RespRegion
+	err = json.Unmarshal(body, &regionResp)
+	if err != nil {
+		return RespRegion{}, err
+	}
+
+	return regionResp, nil
+}
+
+func getLocation(cfg *config, locationName string) (RespLocation, error) {
+	url := "https://pokeapi.co/api/v2/location/" + locationName
+
+	if val, ok := cfg.pokeapiClient.Get(url); ok {
+		var locationResp RespLocation
+		err := json.Unmarshal(val, &locationResp)
+		return locationResp, err
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return RespLocation{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return RespLocation{}, &notFoundError{Kind: "location", Name: locationName}
+	}
+	if res.StatusCode > 299 {
+		return RespLocation{}, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return RespLocation{}, err
+	}
+
+	cfg.pokeapiClient.Add(url, body)
+
+	var locationResp RespLocation
+	err = json.Unmarshal(body, &locationResp)
+	if err != nil {
+		return RespLocation{}, err
+	}
+
+	return locationResp, nil
+}
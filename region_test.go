@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Professor-Goo/pokedexcli/internal/pokecache"
+)
+
+func TestRegionAreasPagerBuildsListOnce(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/region/testland", []byte(
+		`{"name": "testland", "locations": [{"name": "town"}, {"name": "cave"}]}`))
+	cache.Add("https://pokeapi.co/api/v2/location/town", []byte(
+		`{"name": "town", "areas": [{"name": "town-area"}]}`))
+	cache.Add("https://pokeapi.co/api/v2/location/cave", []byte(
+		`{"name": "cave", "areas": [{"name": "cave-1f"}, {"name": "cave-2f"}]}`))
+	cfg := &config{pokeapiClient: cache}
+
+	pager := regionAreasPager(cfg, "testland")
+	first, err := pager.fetch(cfg, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Count != 3 || len(first.Results) != 2 {
+		t.Fatalf("first page = %+v, expected 2 of 3 areas", first)
+	}
+
+	// Later pages, even through a fresh lookup of the pager, must not fetch
+	// the region and its locations again.
+	cfg.pokeapiClient = pokecache.NewCache(time.Minute)
+	second, err := regionAreasPager(cfg, "testland").fetch(cfg, 2, 2)
+	if err != nil {
+		t.Fatalf("second page refetched the region: %v", err)
+	}
+	if len(second.Results) != 1 || second.Results[0].Name != "cave-2f" {
+		t.Errorf("second page = %+v, expected cave-2f", second)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxInputLine = 1024 * 1024
+
+var errUnknownCommand = errors.New("unknown command")
+
+// errExit is returned by the exit command to ask the caller to shut down.
+var errExit = errors.New("exit")
+
+func runREPL(cfg *config, readLine func() (string, error)) error {
+	commands := getCommands()
+
+	for {
+		input, err := readLine()
+		if err == io.EOF {
+			shutdown(cfg)
+			return nil
+		}
+		if err != nil {
+			shutdown(cfg)
+			return err
+		}
+
+		err = runCommand(cfg, commands, input)
+		if err == errExit {
+			shutdown(cfg)
+			return nil
+		}
+		if err != nil {
+			reportError(cfg, err)
+		}
+	}
+}
+
+func runCommand(cfg *config, commands map[string]cliCommand, input string) error {
+	words, err := splitInput(input)
+	if err != nil {
+		return err
+	}
+
+	if len(words) == 0 {
+		return nil
+	}
+
+	commandName := strings.ToLower(words[0])
+	args := words[1:]
+
+	command, exists := commands[commandName]
+	if !exists {
+		if _, ok := cfg.aliases[commandName]; ok {
+			return runAlias(cfg, commands, commandName, args)
+		}
+		return errUnknownCommand
+	}
+
+	return command.callback(cfg, args...)
+}
+
+func reportError(cfg *config, err error) {
+	if err == errUnknownCommand && cfg.outputFormat == "text" {
+		fmt.Fprintln(cfg.errOut, "Unknown command")
+		return
+	}
+	writeResult(cfg.errOut, cfg.outputFormat, errorResult{Error: err.Error(), Seed: cfg.seed})
+}
+
+// newScannerReader reads lines from in, prompting on cfg.out. The prompt is
+// left out while the output format is json or yaml, so scripts piping
+// commands in get output they can parse.
+func newScannerReader(cfg *config, in io.Reader) func() (string, error) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxInputLine)
+
+	return func() (string, error) {
+		if cfg.outputFormat == "text" {
+			fmt.Fprint(cfg.out, "Pokedex > ")
+		}
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", io.EOF
+		}
+		return scanner.Text(), nil
+	}
+}
+
+func shutdown(cfg *config) {
+	emit(cfg, messageResult{"Closing the Pokedex... Goodbye!"})
+	for _, closer := range cfg.closers {
+		if err := closer.Close(); err != nil {
+			fmt.Fprintln(cfg.errOut, "Error:", err)
+		}
+	}
+	cfg.closers = nil
+}
+
+// splitInput splits a command line into words the way a shell does. Words
+// are separated by whitespace, single quotes keep their contents literally,
+// double quotes allow \" and \\ escapes, and a backslash outside quotes
+// escapes the next character. Case is preserved.
+func splitInput(text string) ([]string, error) {
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for i, r := range text {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		raw := text[i : i+size]
+		switch {
+		case escaped:
+			if quote == '"' && r != '"' && r != '\\' {
+				word.WriteRune('\\')
+			}
+			word.WriteString(raw)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteString(raw)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case unicode.IsSpace(r):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteString(raw)
+			inWord = true
+		}
+	}
+
+	if escaped {
+		return nil, errors.New("unfinished escape at end of input")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// splitCommands splits a line on semicolons that are not quoted or escaped.
+func splitCommands(line string) []string {
+	commands := []string{}
+	start := 0
+	forEachUnquoted(line, func(i int, r rune) bool {
+		if r == ';' {
+			commands = append(commands, line[start:i])
+			start = i + 1
+		}
+		return true
+	})
+	return append(commands, line[start:])
+}
+
+// forEachUnquoted calls fn with the offset of every rune in line that is
+// outside quotes and not escaped, stopping early when fn returns false.
+func forEachUnquoted(line string, fn func(i int, r rune) bool) {
+	var quote rune
+	escaped := false
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		default:
+			if !fn(i, r) {
+				return
+			}
+		}
+	}
+}
+
+// quoteWord quotes s so that splitInput reads it back as the same single word.
+func quoteWord(s string) string {
+	if s != "" && !strings.ContainsAny(s, "'\"\\;#$") && strings.IndexFunc(s, unicode.IsSpace) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// normalizeName turns user input into a PokeAPI identifier, so "Mr Mime"
+// becomes "mr-mime".
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
@@ -1,10 +1,14 @@
 package main
 
 import (
+	"io"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
-func TestCleanInput(t *testing.T) {
+func TestSplitInput(t *testing.T) {
 	cases := []struct {
 		input    string
 		expected []string
@@ -15,7 +19,7 @@ func TestCleanInput(t *testing.T) {
 		},
 		{
 			input:    "Charmander Bulbasaur PIKACHU",
-			expected: []string{"charmander", "bulbasaur", "pikachu"},
+			expected: []string{"Charmander", "Bulbasaur", "PIKACHU"},
 		},
 		{
 			input:    "  ",
@@ -27,12 +31,36 @@ func TestCleanInput(t *testing.T) {
 		},
 		{
 			input:    "  HELLO    WORLD  ",
-			expected: []string{"hello", "world"},
+			expected: []string{"HELLO", "WORLD"},
+		},
+		{
+			input:    `nickname pikachu "Sir Fluffy"`,
+			expected: []string{"nickname", "pikachu", "Sir Fluffy"},
+		},
+		{
+			input:    `load '/tmp/my saves/a.pdx'`,
+			expected: []string{"load", "/tmp/my saves/a.pdx"},
+		},
+		{
+			input:    `say "it's \"quoted\"" don\'t a\ b`,
+			expected: []string{"say", `it's "quoted"`, "don't", "a b"},
+		},
+		{
+			input:    `"" 'a'"b"c`,
+			expected: []string{"", "abc"},
+		},
+		{
+			input:    `"C:\dir\n"`,
+			expected: []string{`C:\dir\n`},
 		},
 	}
 
 	for _, c := range cases {
-		actual := cleanInput(c.input)
+		actual, err := splitInput(c.input)
+		if err != nil {
+			t.Errorf("For input '%s', unexpected error: %v", c.input, err)
+			continue
+		}
 
 		// Check the length of the actual slice against the expected slice
 		if len(actual) != len(c.expected) {
@@ -52,3 +80,126 @@ func TestCleanInput(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitInputErrors(t *testing.T) {
+	for _, input := range []string{`"open`, `'open`, `trailing\`, `a "b" 'c`} {
+		if _, err := splitInput(input); err == nil {
+			t.Errorf("For input '%s', expected an error", input)
+		}
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	cases := map[string]string{
+		"Pikachu":            "pikachu",
+		"  Mr Mime ":         "mr-mime",
+		"PASTORIA-city-area": "pastoria-city-area",
+	}
+	for input, expected := range cases {
+		if actual := normalizeName(input); actual != expected {
+			t.Errorf("normalizeName(%q) = %q, expected %q", input, actual, expected)
+		}
+	}
+}
+
+func FuzzSplitInput(f *testing.F) {
+	for _, seed := range []string{"", "catch pikachu", `nickname pikachu "Sir Fluffy"`, `a\ b 'c d' "e\"f"`, `"`, `\`} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		words, err := splitInput(input)
+		if err != nil {
+			return
+		}
+
+		// Quoting every word and splitting again must give the same words.
+		quoted := make([]string, len(words))
+		for i, word := range words {
+			quoted[i] = quoteWord(word)
+		}
+		again, err := splitInput(strings.Join(quoted, " "))
+		if err != nil {
+			t.Fatalf("re-splitting %q: %v", quoted, err)
+		}
+		if !slices.Equal(words, again) {
+			t.Fatalf("round trip of %q gave %q, expected %q", input, again, words)
+		}
+
+		// Without quotes or escapes the result matches plain field splitting.
+		if !strings.ContainsAny(input, `'"\`) && !slices.Equal(words, strings.Fields(input)) {
+			t.Fatalf("splitInput(%q) = %q, expected %q", input, words, strings.Fields(input))
+		}
+	})
+}
+
+func FuzzSplitCommands(f *testing.F) {
+	for _, seed := range []string{"", "a; b", `alias x = "a; b"`, `a\;b`, `'a;' ; b`} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		parts := splitCommands(input)
+		if strings.Join(parts, ";") != input {
+			t.Fatalf("splitCommands(%q) = %q does not rejoin to the input", input, parts)
+		}
+		if !strings.Contains(input, ";") && len(parts) != 1 {
+			t.Fatalf("splitCommands(%q) = %q, expected one command", input, parts)
+		}
+	})
+}
+
+func TestRunREPLStopsAtEOF(t *testing.T) {
+	cases := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "empty input", input: ""},
+		{name: "commands", input: "seed 1\n\nseed\n"},
+		{name: "no trailing newline", input: "seed 1"},
+		{name: "line too long", input: strings.Repeat("a", maxInputLine+1) + "\n", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &config{out: io.Discard, errOut: io.Discard}
+			setSeed(cfg, 1)
+
+			done := make(chan error, 1)
+			go func() {
+				done <- runREPL(cfg, newScannerReader(cfg, strings.NewReader(c.input)))
+			}()
+
+			select {
+			case err := <-done:
+				if c.wantErr && err == nil {
+					t.Errorf("expected an error")
+				}
+				if !c.wantErr && err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("REPL did not stop at end of input")
+			}
+		})
+	}
+}
+
+func TestScannerReaderPrompt(t *testing.T) {
+	for format, expected := range map[string]string{
+		"text": "Pokedex > Seed set to 5\nPokedex > Seed set to 6\nPokedex > Closing the Pokedex... Goodbye!\n",
+		"json": "{\n  \"message\": \"Seed set to 5\"\n}\n{\n  \"message\": \"Seed set to 6\"\n}\n{\n  \"message\": \"Closing the Pokedex... Goodbye!\"\n}\n",
+	} {
+		var out strings.Builder
+		cfg := &config{out: &out, errOut: &out, outputFormat: format}
+		setSeed(cfg, 1)
+
+		if err := runREPL(cfg, newScannerReader(cfg, strings.NewReader("seed 5\nseed 6\n"))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.String() != expected {
+			t.Errorf("%s output = %q, expected %q", format, out.String(), expected)
+		}
+	}
+}
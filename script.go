@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// runScript runs each command in script in order without prompting. Commands
+// are separated by newlines or unquoted semicolons and an unquoted # starts a
+// comment. It returns an error if any command failed.
+func runScript(cfg *config, script string) error {
+	commands := getCommands()
+	failed := 0
+
+	for _, line := range strings.Split(script, "\n") {
+		line = stripComment(line)
+		for _, input := range splitCommands(line) {
+			if strings.TrimSpace(input) == "" {
+				continue
+			}
+
+			err := runCommand(cfg, commands, input)
+			if err == nil {
+				continue
+			}
+			if err == errExit {
+				shutdown(cfg)
+				return scriptResult(failed)
+			}
+
+			reportError(cfg, err)
+			failed++
+			if cfg.stopOnError {
+				return fmt.Errorf("stopped after %q failed", strings.TrimSpace(input))
+			}
+		}
+	}
+
+	return scriptResult(failed)
+}
+
+func scriptResult(failed int) error {
+	if failed > 0 {
+		return fmt.Errorf("%d command(s) failed", failed)
+	}
+	return nil
+}
+
+func stripComment(line string) string {
+	end := len(line)
+	forEachUnquoted(line, func(i int, r rune) bool {
+		if r == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			end = i
+			return false
+		}
+		return true
+	})
+	return line[:end]
+}
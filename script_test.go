@@ -0,0 +1,57 @@
+package main
+
+import (
+	"io"
+	"testing"
+)
+
+func TestRunScript(t *testing.T) {
+	cases := []struct {
+		name     string
+		script   string
+		wantErr  bool
+		wantSeed int64
+	}{
+		{
+			name:     "semicolons and comments",
+			script:   "# set things up\nseed 7; seed # show it\n",
+			wantSeed: 7,
+		},
+		{
+			name:     "failures keep going",
+			script:   "seed 1; bogus; seed 2",
+			wantErr:  true,
+			wantSeed: 2,
+		},
+		{
+			name:     "set -e stops at the first failure",
+			script:   "set -e\nseed 1\nbogus\nseed 2",
+			wantErr:  true,
+			wantSeed: 1,
+		},
+		{
+			name:     "set +e turns it off again",
+			script:   "set -e; set +e; seed 1; seed x; seed 2",
+			wantErr:  true,
+			wantSeed: 2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := &config{out: io.Discard, errOut: io.Discard}
+			setSeed(cfg, 0)
+
+			err := runScript(cfg, c.script)
+			if c.wantErr && err == nil {
+				t.Errorf("expected an error")
+			}
+			if !c.wantErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if cfg.seed != c.wantSeed {
+				t.Errorf("expected seed %d, got %d", c.wantSeed, cfg.seed)
+			}
+		})
+	}
+}
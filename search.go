@@ -0,0 +1,220 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const defaultSearchLimit = 10
+
+// searchKinds lists the kinds search looks through, in the order results are
+// shown.
+var searchKinds = []string{"pokemon", "area", "item", "move"}
+
+// nameIndexEndpoints maps every kind of name that can be indexed, including
+// those only used for suggestions, to its list endpoint.
+var nameIndexEndpoints = map[string]string{
+	"pokemon":  "pokemon",
+	"ability":  "ability",
+	"area":     "location-area",
+	"item":     "item",
+	"move":     "move",
+	"region":   "region",
+	"location": "location",
+	"pokedex":  "pokedex",
+}
+
+type notFoundError struct {
+	Kind        string
+	Name        string
+	Suggestions []string
+}
+
+func (e *notFoundError) Error() string {
+	msg := fmt.Sprintf("no %s named %q", e.Kind, e.Name)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(", did you mean %s?", strings.Join(e.Suggestions, " or "))
+	}
+	return msg
+}
+
+type searchResult struct {
+	Term    string        `json:"term"`
+	Matches []searchMatch `json:"matches"`
+}
+
+type searchMatch struct {
+	Kind  string `json:"kind"`
+	Name  string `json:"name"`
+	score int
+}
+
+func (r searchResult) renderText(w io.Writer) {
+	if len(r.Matches) == 0 {
+		fmt.Fprintf(w, "No matches for %q\n", r.Term)
+		return
+	}
+	fmt.Fprintf(w, "Results for %q:\n", r.Term)
+	for _, match := range r.Matches {
+		fmt.Fprintf(w, " - %s (%s)\n", match.Name, match.Kind)
+	}
+}
+
+func commandSearch(cfg *config, args ...string) error {
+	flags, positional, err := parseFlags(args, []string{"kind", "limit"}, nil)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return fmt.Errorf("usage: search <term> [--kind pokemon|area|item|move] [--limit N]")
+	}
+	limit, err := intFlag(flags, "limit", defaultSearchLimit)
+	if err != nil {
+		return err
+	}
+
+	kinds := []string{}
+	for _, kind := range searchKinds {
+		if flags["kind"] == "" || flags["kind"] == kind {
+			kinds = append(kinds, kind)
+		}
+	}
+	if len(kinds) == 0 {
+		return fmt.Errorf("--kind must be one of pokemon, area, item or move")
+	}
+
+	term := normalizeName(strings.Join(positional, " "))
+	matches, err := searchNames(cfg, term, kinds...)
+	if err != nil {
+		return err
+	}
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return emit(cfg, searchResult{Term: term, Matches: matches})
+}
+
+// searchNames ranks every indexed name of the given kinds against term:
+// exact matches first, then prefixes, then substrings, then names within a
+// few typos.
+func searchNames(cfg *config, term string, kinds ...string) ([]searchMatch, error) {
+	matches := []searchMatch{}
+	for _, kind := range kinds {
+		names, err := getNameIndex(cfg, kind)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if score, ok := matchScore(term, name); ok {
+				matches = append(matches, searchMatch{Kind: kind, Name: name, score: score})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	return matches, nil
+}
+
+func matchScore(term, name string) (int, bool) {
+	switch {
+	case term == "":
+		return 0, false
+	case name == term:
+		return 0, true
+	case strings.HasPrefix(name, term):
+		return 1, true
+	case strings.Contains(name, term):
+		return 2, true
+	}
+
+	distance := levenshtein(term, name)
+	if distance > max(1, len(term)/3) {
+		return 0, false
+	}
+	return 2 + distance, true
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// getNameIndex returns every name of the given kind, fetching the full list
+// once through the cache and keeping it for the rest of the session.
+func getNameIndex(cfg *config, kind string) ([]string, error) {
+	if names, ok := cfg.nameIndex[kind]; ok {
+		return names, nil
+	}
+
+	endpoint, ok := nameIndexEndpoints[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind %q", kind)
+	}
+
+	names := []string{}
+	for resource, err := range listAll(cfg, endpoint) {
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, resource.Name)
+	}
+
+	if cfg.nameIndex == nil {
+		cfg.nameIndex = make(map[string][]string)
+	}
+	cfg.nameIndex[kind] = names
+	return names, nil
+}
+
+// suggestMatches adds the closest known names to a not-found error. Other
+// errors, or a name index that can't be loaded, leave err as it is.
+func suggestMatches(cfg *config, err error) error {
+	var notFound *notFoundError
+	if !errors.As(err, &notFound) {
+		return err
+	}
+
+	matches, indexErr := searchNames(cfg, notFound.Name, notFound.Kind)
+	if indexErr != nil {
+		return err
+	}
+	suggestions := []string{}
+	for _, match := range matches {
+		if len(suggestions) == 3 {
+			break
+		}
+		suggestions = append(suggestions, match.Name)
+	}
+	return &notFoundError{Kind: notFound.Kind, Name: notFound.Name, Suggestions: suggestions}
+}
+
+func completeSearch(cfg *config, args ...string) []string {
+	if len(args) > 0 && args[len(args)-1] == "--kind" {
+		return searchKinds
+	}
+	return nil
+}
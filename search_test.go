@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Professor-Goo/pokedexcli/internal/pokecache"
+)
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"charmandr", "charmander", 1},
+		{"pikachu", "pichu", 2},
+		{"kitten", "sitting", 3},
+	}
+	for _, c := range cases {
+		if actual := levenshtein(c.a, c.b); actual != c.expected {
+			t.Errorf("levenshtein(%q, %q) = %d, expected %d", c.a, c.b, actual, c.expected)
+		}
+	}
+}
+
+func TestSuggestMatches(t *testing.T) {
+	cache := pokecache.NewCache(time.Minute)
+	cache.Add(listURL("pokemon", 0, bulkPageLimit), []byte(
+		`{"count": 3, "results": [{"name": "charmander"}, {"name": "charmeleon"}, {"name": "squirtle"}]}`))
+	cfg := &config{pokeapiClient: cache}
+
+	err := suggestMatches(cfg, &notFoundError{Kind: "pokemon", Name: "charmandr"})
+	expected := `no pokemon named "charmandr", did you mean charmander?`
+	if err == nil || err.Error() != expected {
+		t.Errorf("got %v, expected %s", err, expected)
+	}
+
+	err = suggestMatches(cfg, &notFoundError{Kind: "pokemon", Name: "zzz"})
+	if err == nil || err.Error() != `no pokemon named "zzz"` {
+		t.Errorf("got %v, expected no suggestions", err)
+	}
+
+	cache.Add(listURL("ability", 0, bulkPageLimit), []byte(
+		`{"count": 2, "results": [{"name": "static"}, {"name": "stench"}]}`))
+	err = suggestMatches(cfg, &notFoundError{Kind: "ability", Name: "statik"})
+	if err == nil || err.Error() != `no ability named "statik", did you mean static?` {
+		t.Errorf("got %v, expected static to be suggested", err)
+	}
+
+	other := fmt.Errorf("fetching: 500 Internal Server Error")
+	if err := suggestMatches(cfg, other); err != other {
+		t.Errorf("other errors should pass through unchanged, got %v", err)
+	}
+}
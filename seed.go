@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+)
+
+func setSeed(cfg *config, seed int64) {
+	cfg.seed = seed
+	cfg.rng = rand.New(rand.NewSource(seed))
+}
+
+type seedResult struct {
+	Seed int64 `json:"seed"`
+}
+
+func (r seedResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "Seed: %d\n", r.Seed)
+}
+
+func commandSeed(cfg *config, args ...string) error {
+	if len(args) == 0 {
+		return emit(cfg, seedResult{cfg.seed})
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("usage: seed [<number>]")
+	}
+
+	seed, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("seed must be a number")
+	}
+
+	setSeed(cfg, seed)
+	return emit(cfg, messageResult{fmt.Sprintf("Seed set to %d", seed)})
+}
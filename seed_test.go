@@ -0,0 +1,77 @@
+package main
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Professor-Goo/pokedexcli/internal/pokecache"
+)
+
+func TestSeedReproducesCatches(t *testing.T) {
+	const pokemonJSON = `{
+		"id": 25,
+		"name": "pikachu",
+		"base_experience": 112,
+		"abilities": [
+			{"ability": {"name": "static"}, "is_hidden": false, "slot": 1},
+			{"ability": {"name": "lightning-rod"}, "is_hidden": true, "slot": 3}
+		],
+		"held_items": [
+			{"item": {"name": "light-ball"}, "version_details": [{"rarity": 50, "version": {"name": "diamond"}}]}
+		]
+	}`
+
+	type catchOutcome struct {
+		Caught   bool
+		Level    int
+		Ability  string
+		Shiny    bool
+		HeldItem string
+	}
+
+	catchAll := func(seed int64) []catchOutcome {
+		cache := pokecache.NewCache(time.Minute)
+		cache.Add("https://pokeapi.co/api/v2/pokemon/pikachu", []byte(pokemonJSON))
+		cfg := &config{
+			pokeapiClient: cache,
+			caughtPokemon: make(map[string]ownedPokemon),
+			pokedex:       make(map[int]dexRecord),
+			wildLevels:    map[string]levelRange{"pikachu": {min: 3, max: 40}},
+			shinyOdds:     2,
+			bag:           make(map[string]int),
+			out:           io.Discard,
+		}
+		setSeed(cfg, seed)
+
+		outcomes := []catchOutcome{}
+		for i := 0; i < 20; i++ {
+			if err := commandCatch(cfg, "pikachu"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			pokemon, caught := cfg.caughtPokemon["pikachu"]
+			outcomes = append(outcomes, catchOutcome{caught, pokemon.Level, pokemon.Ability, pokemon.Shiny, pokemon.HeldItem})
+			// Release it so the next throw is a fresh catch.
+			delete(cfg.caughtPokemon, "pikachu")
+		}
+		return outcomes
+	}
+
+	first := catchAll(42)
+	second := catchAll(42)
+
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("expected identical catches for the same seed, got %+v and %+v", first, second)
+	}
+
+	caught := 0
+	for _, outcome := range first {
+		if outcome.Caught {
+			caught++
+		}
+	}
+	if caught == 0 || caught == len(first) {
+		t.Errorf("expected seed 42 to give both catches and escapes, got %d of %d caught", caught, len(first))
+	}
+}
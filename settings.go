@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultShinyOdds = 4096
+	defaultLanguage  = "en"
+)
+
+// languages are the PokeAPI language names with localized text.
+var languages = []string{"cs", "de", "en", "es", "fr", "it", "ja", "ja-hrkt", "ko", "roomaji", "zh-hans", "zh-hant"}
+
+type settingsResult struct {
+	ShinyOdds int    `json:"shiny_odds"`
+	Version   string `json:"version"`
+	Language  string `json:"language"`
+	Output    string `json:"output"`
+}
+
+func (r settingsResult) renderText(w io.Writer) {
+	fmt.Fprintf(w, "shiny-odds: 1/%d\n", r.ShinyOdds)
+	fmt.Fprintf(w, "version: %s\n", r.Version)
+	fmt.Fprintf(w, "language: %s\n", r.Language)
+	fmt.Fprintf(w, "output: %s\n", r.Output)
+}
+
+func commandSet(cfg *config, args ...string) error {
+	if len(args) == 0 {
+		version := cfg.gameVersion
+		if version == "" {
+			version = "any"
+		}
+		return emit(cfg, settingsResult{
+			ShinyOdds: cfg.shinyOdds,
+			Version:   version,
+			Language:  cfg.language,
+			Output:    cfg.outputFormat,
+		})
+	}
+	if len(args) == 1 && (args[0] == "-e" || args[0] == "+e") {
+		cfg.stopOnError = args[0] == "-e"
+		return nil
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("usage: set <option> <value>")
+	}
+
+	option, value := normalizeName(args[0]), normalizeName(args[1])
+	switch option {
+	case "shiny-odds":
+		odds, err := strconv.Atoi(value)
+		if err != nil || odds < 1 {
+			return fmt.Errorf("shiny-odds must be a positive number")
+		}
+		cfg.shinyOdds = odds
+	case "version":
+		if value == "any" {
+			value = ""
+		}
+		cfg.gameVersion = value
+	case "language":
+		if !slices.Contains(languages, value) {
+			return fmt.Errorf("language must be one of %s", strings.Join(languages, ", "))
+		}
+		cfg.language = value
+	case "output":
+		if !validOutputFormat(value) {
+			return fmt.Errorf("output must be one of %s", strings.Join(outputFormats, ", "))
+		}
+		cfg.outputFormat = value
+	default:
+		return fmt.Errorf("unknown option %q", option)
+	}
+
+	return emit(cfg, messageResult{fmt.Sprintf("%s set to %s", option, normalizeName(args[1]))})
+}
+
+// localized returns the index of the entry in language, falling back to
+// English, or -1 if there is neither. languageAt reports the language of the
+// i-th of n entries.
+func localized(language string, n int, languageAt func(i int) string) int {
+	fallback := -1
+	for i := 0; i < n; i++ {
+		switch languageAt(i) {
+		case language:
+			return i
+		case defaultLanguage:
+			if fallback < 0 {
+				fallback = i
+			}
+		}
+	}
+	return fallback
+}
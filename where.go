@@ -0,0 +1,189 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+type RespLocationAreaEncounters []struct {
+	LocationArea   namedAPIResource `json:"location_area"`
+	VersionDetails []struct {
+		MaxChance        int              `json:"max_chance"`
+		Version          namedAPIResource `json:"version"`
+		EncounterDetails []struct {
+			Chance          int                `json:"chance"`
+			ConditionValues []namedAPIResource `json:"condition_values"`
+			MaxLevel        int                `json:"max_level"`
+			MinLevel        int                `json:"min_level"`
+			Method          namedAPIResource   `json:"method"`
+		} `json:"encounter_details"`
+	} `json:"version_details"`
+}
+
+type whereResult struct {
+	Pokemon  string         `json:"pokemon"`
+	Version  string         `json:"version,omitempty"`
+	Versions []whereVersion `json:"versions"`
+}
+
+type whereVersion struct {
+	Version   string          `json:"version"`
+	Locations []whereLocation `json:"locations"`
+}
+
+type whereLocation struct {
+	Area       string   `json:"area"`
+	Method     string   `json:"method"`
+	MinLevel   int      `json:"min_level"`
+	MaxLevel   int      `json:"max_level"`
+	Chance     int      `json:"chance"`
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+func (r whereResult) renderText(w io.Writer) {
+	if len(r.Versions) == 0 {
+		if r.Version != "" {
+			fmt.Fprintf(w, "%s can't be found in the wild in %s\n", r.Pokemon, r.Version)
+		} else {
+			fmt.Fprintf(w, "%s can't be found in the wild\n", r.Pokemon)
+		}
+		return
+	}
+
+	areaWidth, methodWidth, levelWidth := 0, 0, 0
+	for _, version := range r.Versions {
+		for _, loc := range version.Locations {
+			areaWidth = max(areaWidth, len(loc.Area))
+			methodWidth = max(methodWidth, len(loc.Method))
+			levelWidth = max(levelWidth, len(levelRangeText(loc.MinLevel, loc.MaxLevel)))
+		}
+	}
+
+	fmt.Fprintf(w, "Where to find %s:\n", r.Pokemon)
+	for _, version := range r.Versions {
+		fmt.Fprintf(w, "%s:\n", version.Version)
+		for _, loc := range version.Locations {
+			fmt.Fprintf(w, "  %-*s  %-*s  %-*s  %3d%%%s\n", areaWidth, loc.Area, methodWidth, loc.Method,
+				levelWidth, levelRangeText(loc.MinLevel, loc.MaxLevel), loc.Chance, conditionsText(loc.Conditions))
+		}
+	}
+}
+
+func commandWhere(cfg *config, args ...string) error {
+	flags, positional, err := parseFlags(args, []string{"version"}, nil)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("usage: where <pokemon_name> [--version v]")
+	}
+
+	version := cfg.gameVersion
+	if v, ok := flags["version"]; ok {
+		version = normalizeName(v)
+		if version == "any" {
+			version = ""
+		}
+	}
+
+	pokemon, err := resolvePokemon(cfg, normalizeName(positional[0]))
+	if err != nil {
+		return err
+	}
+
+	encounters, err := getLocationAreaEncounters(cfg, pokemon.LocationAreaEncounters)
+	if err != nil {
+		return err
+	}
+
+	return emit(cfg, whereResult{
+		Pokemon:  pokemon.Name,
+		Version:  version,
+		Versions: groupEncountersByVersion(encounters, version),
+	})
+}
+
+// groupEncountersByVersion lists the areas, methods, levels and chances per
+// game version, oldest version first. Chances of several slots with the same
+// method and conditions add up; conditional slots get rows of their own, as in
+// explore.
+func groupEncountersByVersion(encounters RespLocationAreaEncounters, onlyVersion string) []whereVersion {
+	type key struct{ version, area, method, conditions string }
+	rows := map[key]*whereLocation{}
+	order := []key{}
+	versionIDs := map[string]int{}
+
+	for _, encounter := range encounters {
+		for _, details := range encounter.VersionDetails {
+			if onlyVersion != "" && details.Version.Name != onlyVersion {
+				continue
+			}
+			versionIDs[details.Version.Name] = resourceID(details.Version.URL)
+			for _, detail := range details.EncounterDetails {
+				conditions := conditionNames(detail.ConditionValues)
+				k := key{details.Version.Name, encounter.LocationArea.Name, detail.Method.Name, strings.Join(conditions, ",")}
+				row, ok := rows[k]
+				if !ok {
+					row = &whereLocation{Area: k.area, Method: k.method, MinLevel: detail.MinLevel, MaxLevel: detail.MaxLevel, Conditions: conditions}
+					rows[k] = row
+					order = append(order, k)
+				}
+				row.MinLevel = min(row.MinLevel, detail.MinLevel)
+				row.MaxLevel = max(row.MaxLevel, detail.MaxLevel)
+				row.Chance += detail.Chance
+			}
+		}
+	}
+
+	versions := []whereVersion{}
+	for _, k := range order {
+		i := slices.IndexFunc(versions, func(v whereVersion) bool { return v.Version == k.version })
+		if i < 0 {
+			versions = append(versions, whereVersion{Version: k.version})
+			i = len(versions) - 1
+		}
+		versions[i].Locations = append(versions[i].Locations, *rows[k])
+	}
+	slices.SortStableFunc(versions, func(a, b whereVersion) int {
+		return cmp.Compare(versionIDs[a.Version], versionIDs[b.Version])
+	})
+	return versions
+}
+
+func getLocationAreaEncounters(cfg *config, url string) (RespLocationAreaEncounters, error) {
+	if val, ok := cfg.pokeapiClient.Get(url); ok {
+		var encountersResp RespLocationAreaEncounters
+		err := json.Unmarshal(val, &encountersResp)
+		return encountersResp, err
+	}
+
+	res, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode > 299 {
+		return nil, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.pokeapiClient.Add(url, body)
+
+	var encountersResp RespLocationAreaEncounters
+	err = json.Unmarshal(body, &encountersResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return encountersResp, nil
+}